@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"errors"
+	"net"
+	"os/exec"
+
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+// Exit codes of gh-wrun. They are part of the interface for scripts
+// and must not change between versions.
+const (
+	exitOK          = 0
+	exitFailure     = 1
+	exitUsage       = 2
+	exitCanceled    = 3
+	exitGh          = 4
+	exitInput       = 5
+	exitDispatch    = 6
+	exitWatch       = 7
+	exitTimeout     = 8
+	exitCall        = 9
+	exitRunFailure  = 10
+	exitRunCanceled = 11
+	exitRunTimedOut = 12
+	exitRunOther    = 13
+)
+
+// Error codes of the JSON output, one per exit code.
+var errorCodes = map[int]string{
+	exitFailure:     "error",
+	exitUsage:       "usage",
+	exitCanceled:    "canceled",
+	exitGh:          "gh_unavailable",
+	exitInput:       "invalid_input",
+	exitDispatch:    "dispatch_failed",
+	exitWatch:       "watch_failed",
+	exitTimeout:     "timeout",
+	exitCall:        "call_failed",
+	exitRunFailure:  "run_failure",
+	exitRunCanceled: "run_cancelled",
+	exitRunTimedOut: "run_timed_out",
+	exitRunOther:    "run_not_successful",
+}
+
+// exitError is an error with the exit code of its failure class.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExit classifies err with the exit code, or returns nil if err is nil.
+func withExit(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the exit code for err.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	// gh is not usable without credentials, whichever step failed
+	if errors.Is(err, subproc.ErrNotAuthenticated) {
+		return exitGh
+	}
+
+	// a call timing out is not the fault of the step it happened in
+	var timeoutErr *subproc.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return exitTimeout
+	}
+
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return exitFailure
+}
+
+// inputExitCode returns the exit code for err of collecting the answers.
+// Failed gh, git or GitHub API calls are not caused by the given values.
+func inputExitCode(err error) int {
+	var (
+		ghErr  *subproc.GhError
+		netErr net.Error
+	)
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return exitGh
+	case errors.As(err, &ghErr), errors.As(err, &netErr):
+		return exitCall
+	default:
+		return exitInput
+	}
+}
+
+// runExitCode returns the exit code matching the conclusion of a completed run.
+func runExitCode(run subproc.GhRun) int {
+	switch run.Conclusion {
+	case subproc.GhRunConclusionSuccess:
+		return exitOK
+	case subproc.GhRunConclusionFailure:
+		return exitRunFailure
+	case subproc.GhRunConclusionCancelled:
+		return exitRunCanceled
+	case subproc.GhRunConclusionTimedOut:
+		return exitRunTimedOut
+	default:
+		return exitRunOther
+	}
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// inputFlags collects repeated --input key=value flags.
+type inputFlags []struct{ Key, Value string }
+
+func (f *inputFlags) String() string {
+	s := []string{}
+	for _, i := range *f {
+		s = append(s, i.Key+"="+i.Value)
+	}
+	return strings.Join(s, ",")
+}
+
+func (f *inputFlags) Set(v string) error {
+	key, value, ok := strings.Cut(v, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid input %q, expected key=value", v)
+	}
+
+	*f = append(*f, struct{ Key, Value string }{Key: key, Value: value})
+	return nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/t4kamura/gh-wrun/internal/history"
+	"github.com/t4kamura/gh-wrun/internal/input"
+	"github.com/t4kamura/gh-wrun/internal/interactive"
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+// selectHistoryEntry returns a dispatch of the target repository from the history.
+// If pick is true, the user selects one, otherwise the last one is returned.
+func selectHistoryEntry(ctx context.Context, target subproc.Repo, pick bool) (history.Entry, error) {
+	repo, err := subproc.GetRepositoryWithOwner(ctx, target)
+	if err != nil {
+		return history.Entry{}, err
+	}
+
+	s, err := history.Open()
+	if err != nil {
+		return history.Entry{}, err
+	}
+
+	entries := s.ForRepo(repo)
+	if len(entries) == 0 {
+		return history.Entry{}, errors.New("No history found for " + repo)
+	}
+
+	if !pick {
+		return entries[0], nil
+	}
+
+	labels := []string{}
+	for i, e := range entries {
+		labels = append(labels, fmt.Sprintf("%d. %s", i+1, e))
+	}
+
+	answer, err := interactive.AskChoices("Select a dispatch to re-run", labels, labels[0])
+	if err != nil {
+		return history.Entry{}, err
+	}
+
+	for i, l := range labels {
+		if l == answer {
+			return entries[i], nil
+		}
+	}
+
+	return history.Entry{}, errors.New("No history entry found")
+}
+
+// replayOptions returns opts filled with the answers of e.
+// Inputs given explicitly take precedence over the recorded ones.
+func replayOptions(opts input.Options, e history.Entry) input.Options {
+	if opts.Ref == "" {
+		opts.Ref = e.Branch
+	}
+	if opts.Workflow == "" {
+		opts.Workflow = e.WorkflowPath
+	}
+	opts.Inputs = append(append([]struct{ Key, Value string }{}, e.Inputs...), opts.Inputs...)
+	opts.Replay = true
+	opts.ReplayInputNames = e.InputNames
+
+	return opts
+}
+
+// recordHistory adds the confirmed answers to the history.
+func recordHistory(ctx context.Context, r *input.InputResult) error {
+	repo, err := subproc.GetRepositoryWithOwner(ctx, r.Workflow.Repo)
+	if err != nil {
+		return err
+	}
+
+	s, err := history.Open()
+	if err != nil {
+		return err
+	}
+
+	s.Add(history.Entry{
+		Repo:         repo,
+		Branch:       r.Branch,
+		WorkflowId:   string(r.Workflow.Id),
+		WorkflowPath: r.Workflow.Path,
+		Inputs:       r.WorkflowInputs,
+		InputNames:   r.WorkflowInputNames,
+		Time:         time.Now(),
+	})
+
+	return s.Save()
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/t4kamura/gh-wrun/internal/input"
+)
+
+// Status values of the JSON output.
+const (
+	statusFailed     = "failed"
+	statusDryRun     = "dry_run"
+	statusDispatched = "dispatched"
+	statusCompleted  = "completed"
+)
+
+// result is the JSON output of gh-wrun, documented in the README.
+type result struct {
+	Repo       string            `json:"repo,omitempty"`
+	Workflow   *resultWorkflow   `json:"workflow,omitempty"`
+	Ref        string            `json:"ref,omitempty"`
+	Inputs     map[string]string `json:"inputs"`
+	RunId      string            `json:"runId,omitempty"`
+	RunNumber  int               `json:"runNumber,omitempty"`
+	RunUrl     string            `json:"runUrl,omitempty"`
+	Status     string            `json:"status"`
+	Conclusion string            `json:"conclusion,omitempty"`
+	Error      *resultError      `json:"error,omitempty"`
+	ExitCode   int               `json:"exitCode"`
+}
+
+type resultWorkflow struct {
+	Id   string `json:"id"`
+	Path string `json:"path"`
+	Name string `json:"name"`
+}
+
+type resultError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// setAnswers records the confirmed answers in res.
+func (res *result) setAnswers(r *input.InputResult) {
+	res.Workflow = &resultWorkflow{
+		Id:   string(r.Workflow.Id),
+		Path: r.Workflow.Path,
+		Name: r.Workflow.Name,
+	}
+	res.Ref = r.Branch
+	for _, i := range r.WorkflowInputs {
+		res.Inputs[i.Key] = i.Value
+	}
+}
+
+// setError records err and its exit code in res.
+func (res *result) setError(err error) {
+	res.ExitCode = exitCode(err)
+	if err == nil {
+		return
+	}
+
+	// a completed run keeps its status, only the conclusion failed
+	if res.Status != statusCompleted {
+		res.Status = statusFailed
+	}
+	res.Error = &resultError{
+		Code:    errorCodes[res.ExitCode],
+		Message: err.Error(),
+	}
+}
+
+// write writes res as JSON to w.
+func (res *result) write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/t4kamura/gh-wrun/internal/input"
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+func TestResultWrite(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status string
+		err    error
+		want   string
+	}{
+		{
+			name:   "dispatched",
+			status: statusDispatched,
+			want: `{
+  "repo": "t4kamura/gh-wrun",
+  "workflow": {
+    "id": "123",
+    "path": ".github/workflows/deploy.yml",
+    "name": "Deploy"
+  },
+  "ref": "main",
+  "inputs": {
+    "env": "prod"
+  },
+  "status": "dispatched",
+  "exitCode": 0
+}
+`,
+		},
+		{
+			name:   "dispatch failed",
+			status: "",
+			err:    withExit(exitDispatch, errors.New("HTTP 422")),
+			want: `{
+  "repo": "t4kamura/gh-wrun",
+  "workflow": {
+    "id": "123",
+    "path": ".github/workflows/deploy.yml",
+    "name": "Deploy"
+  },
+  "ref": "main",
+  "inputs": {
+    "env": "prod"
+  },
+  "status": "failed",
+  "error": {
+    "code": "dispatch_failed",
+    "message": "HTTP 422"
+  },
+  "exitCode": 6
+}
+`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			res := &result{Repo: "t4kamura/gh-wrun", Inputs: map[string]string{}}
+			res.setAnswers(&input.InputResult{
+				Branch:         "main",
+				Workflow:       subproc.GhWorkflow{Id: "123", Name: "Deploy", Path: ".github/workflows/deploy.yml"},
+				WorkflowInputs: []struct{ Key, Value string }{{Key: "env", Value: "prod"}},
+			})
+			res.Status = test.status
+			res.setError(test.err)
+
+			var buf bytes.Buffer
+			if err := res.write(&buf); err != nil {
+				t.Fatalf("Unexpected error: %s\n", err)
+			}
+
+			if buf.String() != test.want {
+				t.Errorf("Expected is %s but got %s\n", test.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: exitOK},
+		{name: "unclassified", err: errors.New("boom"), want: exitFailure},
+		{name: "classified", err: withExit(exitCanceled, input.ErrCanceled), want: exitCanceled},
+		{name: "not authenticated", err: withExit(exitDispatch, &subproc.GhError{Kind: subproc.ErrNotAuthenticated}), want: exitGh},
+		{name: "timeout", err: withExit(exitInput, &subproc.TimeoutError{Call: "gh workflow list", Timeout: time.Second}), want: exitTimeout},
+		{name: "run conclusion", err: withExit(runExitCode(subproc.GhRun{Conclusion: "timed_out"}), errors.New("timed out")), want: exitRunTimedOut},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			if got := exitCode(test.err); got != test.want {
+				t.Errorf("Expected is %d but got %d\n", test.want, got)
+			}
+		})
+	}
+}
+
+func TestInputExitCode(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "invalid value", err: errors.New("Workflow \"deploy\" not found"), want: exitInput},
+		{name: "gh missing", err: fmt.Errorf("Failed to list workflows: %w", exec.ErrNotFound), want: exitGh},
+		{name: "failed call", err: fmt.Errorf("Failed to list workflows: %w", &subproc.GhError{Message: "Server Error", Status: 500}), want: exitCall},
+		{name: "transport", err: &url.Error{Op: "Get", URL: "https://api.github.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, want: exitCall},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			if got := inputExitCode(test.err); got != test.want {
+				t.Errorf("Expected is %d but got %d\n", test.want, got)
+			}
+		})
+	}
+}
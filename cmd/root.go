@@ -1,12 +1,21 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/t4kamura/gh-wrun/internal/cache"
 	"github.com/t4kamura/gh-wrun/internal/input"
+	"github.com/t4kamura/gh-wrun/internal/interactive"
+	"github.com/t4kamura/gh-wrun/internal/subproc"
 	ver "github.com/t4kamura/gh-wrun/internal/version"
 )
 
@@ -15,43 +24,329 @@ const (
 	requiredGhVersion = "2.35.0"
 )
 
+// options holds the command line flags.
+type options struct {
+	branchSelect       bool
+	workflow           string
+	all                bool
+	showUndispatchable bool
+	ref                string
+	inputs             inputFlags
+	yes                bool
+	remote             string
+	preset             string
+	repo               string
+	last               bool
+	pickHistory        bool
+	rest               bool
+	dryRun             bool
+	watch              bool
+	web                bool
+	json               bool
+	refresh            bool
+	timeout            time.Duration
+}
+
 func Execute() {
+	var o options
+
 	v := flag.Bool("v", false, "show version")
-	b := flag.Bool("b", false, "first interactively select a git branch name")
+	flag.BoolVar(&o.branchSelect, "b", false, "first interactively select a git branch or tag")
+	flag.StringVar(&o.workflow, "workflow", "", "workflow name, file name or ID to run")
+	flag.BoolVar(&o.all, "all", false, "list disabled workflows too, offering to enable the selected one")
+	flag.BoolVar(&o.showUndispatchable, "show-undispatchable", false, "list workflows without a workflow_dispatch trigger too, greyed out")
+	flag.StringVar(&o.ref, "ref", "", "git branch or tag to run the workflow on")
+	flag.BoolVar(&o.yes, "yes", false, "run without confirmation")
+	flag.StringVar(&o.remote, "remote", "", "git remote to list branches from with -b")
+	flag.StringVar(&o.preset, "preset", "", "pre-fill the answers from the named preset")
+	flag.StringVar(&o.repo, "R", "", "target repository in `[HOST/]OWNER/REPO` format")
+	flag.StringVar(&o.repo, "repo", "", "target repository in `[HOST/]OWNER/REPO` format")
+	flag.BoolVar(&o.last, "last", false, "re-run the last dispatch with the same answers")
+	flag.BoolVar(&o.rest, "rest", false, "call the GitHub REST API directly instead of gh subcommands")
+	flag.BoolVar(&o.dryRun, "dry-run", false, "print the equivalent gh and curl commands instead of running the workflow")
+	flag.BoolVar(&o.watch, "watch", false, "wait for the run to finish and exit with its conclusion")
+	flag.BoolVar(&o.web, "web", false, "open the created run in the browser")
+	flag.BoolVar(&o.json, "json", false, "print the result as JSON to stdout")
+	flag.BoolVar(&o.refresh, "refresh", false, "ignore the cached workflows, workflow files and environments")
+	flag.DurationVar(&o.timeout, "timeout", subproc.DefaultCallTimeout, "time limit of each gh, git and API call, 0 for none")
+	flag.Var(&o.inputs, "input", "workflow input as `key=value` (can be repeated)")
 	flag.Parse()
 
 	if *v {
 		fmt.Printf("gh-wrun version %s\n", version)
-		os.Exit(0)
+		os.Exit(exitOK)
 	}
 
-	if len(flag.Args()) != 0 {
+	// "history" is the only subcommand, it picks a dispatch to re-run
+	o.pickHistory = len(flag.Args()) == 1 && flag.Arg(0) == "history"
+	if len(flag.Args()) != 0 && !o.pickHistory {
 		flag.Usage()
-		os.Exit(1)
+		os.Exit(exitUsage)
 	}
 
-	result, err := ver.CheckGhVersion(requiredGhVersion)
-	if err != nil {
-		log.Fatal(err)
+	// keep stdout for the JSON output
+	out := io.Writer(os.Stdout)
+	if o.json {
+		out = os.Stderr
+		interactive.SetOutput(os.Stderr)
+	}
+
+	// Ctrl-C stops the running calls, a second one exits right away
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	ctx = subproc.WithCallTimeout(ctx, o.timeout)
+
+	res := &result{Inputs: map[string]string{}}
+	err := run(ctx, o, res, out)
+	// an interruption by the user is not a failure
+	if err != nil && (ctx.Err() != nil || errors.Is(err, interactive.ErrInterrupted)) {
+		err = withExit(exitCanceled, input.ErrCanceled)
 	}
+	res.setError(err)
+
+	if o.json {
+		if err := res.write(os.Stdout); err != nil {
+			log.Print(err)
+		}
+	} else if err != nil {
+		log.Print(err)
+	}
+
+	os.Exit(res.ExitCode)
+}
 
-	if !result {
-		log.Fatalf("gh-wrun requires gh version %s or later", requiredGhVersion)
+// run dispatches the workflow according to o and records the outcome in res.
+// Messages for the user are written to out.
+func run(ctx context.Context, o options, res *result, out io.Writer) error {
+	store, err := cache.Open(o.refresh)
+	if err != nil {
+		log.Printf("Failed to open the cache: %s", err)
+	} else {
+		defer func() {
+			if err := store.Save(); err != nil {
+				log.Printf("Failed to save the cache: %s", err)
+			}
+		}()
 	}
 
-	r, err := input.NewInputResult(!*b)
+	// gh is checked in the background while the answers are collected
+	versionErr := make(chan error, 1)
+	go func() { versionErr <- checkGhVersion(ctx, store) }()
 
+	repo, err := subproc.ParseRepo(o.repo)
 	if err != nil {
-		log.Fatal(err)
+		return withExit(exitUsage, err)
+	}
+	res.Repo = repo.String()
+
+	var client subproc.Client = subproc.NewGhClient(repo)
+	if o.rest {
+		if client, err = subproc.NewRestClient(ctx, repo); err != nil {
+			return withExit(exitGh, err)
+		}
+	}
+	var c subproc.Cache
+	if store != nil {
+		c = store
+	}
+	client = subproc.NewProgressClient(subproc.NewCachingClient(client, repo, c))
+
+	opts := input.Options{
+		BranchAuto:         !o.branchSelect,
+		Ref:                o.ref,
+		Workflow:           o.workflow,
+		Inputs:             o.inputs,
+		Yes:                o.yes,
+		Interactive:        interactive.IsTerminal(),
+		Preset:             o.preset,
+		Repo:               repo,
+		Remote:             o.remote,
+		All:                o.all,
+		ShowUndispatchable: o.showUndispatchable,
+		Client:             client,
+		Prefetch:           true,
+		DryRun:             o.dryRun,
+		Out:                out,
+	}
+
+	// only --last replays the newest dispatch without asking
+	if o.pickHistory && !o.last && !opts.Interactive {
+		return withExit(exitInput, errors.New("The history picker needs a terminal, use --last to re-run the last dispatch"))
+	}
+	if o.last || o.pickHistory {
+		e, err := selectHistoryEntry(ctx, repo, o.pickHistory && opts.Interactive)
+		if err != nil {
+			return withExit(exitInput, err)
+		}
+		opts = replayOptions(opts, e)
+	}
+
+	r, err := input.NewInputResult(ctx, opts)
+	if vErr := <-versionErr; vErr != nil {
+		return withExit(exitGh, vErr)
+	}
+
+	if errors.Is(err, input.ErrCanceled) {
+		return withExit(exitCanceled, err)
+	} else if err != nil {
+		return withExit(inputExitCode(err), err)
 	}
 
 	if !r.IsRun {
-		log.Fatal("Canceled")
+		return withExit(exitCanceled, input.ErrCanceled)
+	}
+
+	if res.Repo == "" {
+		res.Repo, _ = subproc.GetRepositoryWithOwner(ctx, repo)
+	}
+	res.setAnswers(r)
+
+	if o.dryRun {
+		res.Status = statusDryRun
+		return printDryRun(ctx, r, out)
+	}
+
+	if err := recordHistory(ctx, r); err != nil {
+		log.Printf("Failed to record history: %s", err)
 	}
 
-	if err := r.Workflow.Run(r.Branch, r.WorkflowInputs); err != nil {
-		log.Fatal(err)
+	if r.EnableWorkflow {
+		if err := client.EnableWorkflow(ctx, r.Workflow); err != nil {
+			return withExit(exitDispatch, fmt.Errorf("Failed to enable the workflow: %w", err))
+		}
+		fmt.Fprintf(out, "Workflow %s enabled\n", r.Workflow.Name)
 	}
 
-	fmt.Println("Workflow started")
+	// the runs existing before the dispatch cannot be the created one
+	actor, knownRuns := listKnownRuns(ctx, client, r)
+
+	dispatchedAt := time.Now()
+	if err := client.Dispatch(ctx, r.Workflow, r.Branch, r.WorkflowInputs); err != nil {
+		return withExit(exitDispatch, err)
+	}
+	res.Status = statusDispatched
+
+	fmt.Fprintln(out, "Workflow started")
+
+	ghRun, err := subproc.FindDispatchedRun(ctx, client, r.Workflow, r.Branch, actor, dispatchedAt, knownRuns)
+	if err != nil {
+		if o.watch {
+			return withExit(exitWatch, err)
+		}
+		log.Printf("Failed to find the created run: %s", err)
+		return nil
+	}
+	res.RunId = string(ghRun.Id)
+	res.RunNumber = ghRun.Number
+	res.RunUrl = ghRun.Url
+
+	if o.json {
+		fmt.Fprintf(out, "Run %s\n", ghRun)
+	} else {
+		r.RenderRunTable(ghRun.Number, ghRun.Url)
+	}
+
+	if o.web {
+		if err := subproc.OpenRun(ctx, r.Workflow.Repo, ghRun.Id); err != nil {
+			log.Printf("Failed to open the run in the browser: %s", err)
+		}
+	}
+
+	if o.watch {
+		return watchRun(ctx, r, ghRun, res, out)
+	}
+
+	return nil
+}
+
+// ghVersionTTL is how long a successful gh version check is cached.
+const ghVersionTTL = 24 * time.Hour
+
+// checkGhVersion checks that gh is recent enough.
+// Only a successful check is cached, so that an upgrade is noticed right away.
+func checkGhVersion(ctx context.Context, store *cache.Store) error {
+	key := "gh-version:" + requiredGhVersion
+	var ok bool
+	if store != nil && store.Get(key, ghVersionTTL, &ok) && ok {
+		return nil
+	}
+
+	ok, err := ver.CheckGhVersion(ctx, requiredGhVersion)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("gh-wrun requires gh version %s or later", requiredGhVersion)
+	}
+
+	if store != nil {
+		store.Put(key, ok)
+	}
+	return nil
+}
+
+// listKnownRuns returns the actor whose runs are searched for the created run,
+// and its runs of the workflow on the ref before the dispatch.
+func listKnownRuns(ctx context.Context, client subproc.Client, r *input.InputResult) (string, []subproc.GhRun) {
+	// the GITHUB_TOKEN of Actions cannot read the user, the runs of any actor are searched then
+	actor, err := subproc.GetCurrentUser(ctx, r.Workflow.Repo)
+	if err != nil {
+		actor = ""
+	}
+
+	// without them the created run is only matched by its creation time
+	runs, _ := client.ListDispatchRuns(ctx, r.Workflow, r.Branch, actor)
+	return actor, runs
+}
+
+// printDryRun prints the commands that would dispatch the workflow.
+func printDryRun(ctx context.Context, r *input.InputResult, out io.Writer) error {
+	repo, err := subproc.ResolveRepo(ctx, r.Workflow.Repo)
+	if err != nil {
+		return err
+	}
+
+	curl, err := subproc.DispatchCurl(repo, r.Workflow, r.Branch, r.WorkflowInputs)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "# gh")
+	if r.EnableWorkflow {
+		fmt.Fprintln(out, subproc.EnableCommand(r.Workflow))
+	}
+	fmt.Fprintln(out, subproc.DispatchCommand(r.Workflow, r.Branch, r.WorkflowInputs))
+	fmt.Fprintln(out, "# curl")
+	fmt.Fprintln(out, curl)
+
+	return nil
+}
+
+// watchRun waits for the run created by the dispatch to finish.
+// It returns an error classified by the run conclusion if it did not succeed.
+func watchRun(ctx context.Context, r *input.InputResult, run subproc.GhRun, res *result, out io.Writer) error {
+	fmt.Fprintf(out, "Watching run %s\n", run)
+
+	// gh run watch only fails on errors of its own, the conclusion is checked below
+	if err := subproc.WatchRun(ctx, r.Workflow.Repo, run.Id, out); err != nil {
+		return withExit(exitWatch, err)
+	}
+
+	run, err := subproc.GetRun(ctx, r.Workflow.Repo, run.Id)
+	if err != nil {
+		return withExit(exitWatch, err)
+	}
+	res.Status = statusCompleted
+	res.Conclusion = run.Conclusion
+
+	fmt.Fprintf(out, "Run %s completed with %s\n", run, run.Conclusion)
+
+	if code := runExitCode(run); code != exitOK {
+		return withExit(code, fmt.Errorf("Run %s concluded with %s", run, run.Conclusion))
+	}
+	return nil
 }
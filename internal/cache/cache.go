@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/t4kamura/gh-wrun/internal/config"
+)
+
+const (
+	fileName = "cache.json"
+	// maxAge is the age after which entries are dropped, whatever their TTL.
+	maxAge = 30 * 24 * time.Hour
+)
+
+// Entry is a cached value and the time it was stored.
+type Entry struct {
+	Value json.RawMessage `json:"value"`
+	Time  time.Time       `json:"time"`
+}
+
+// file is the content of the cache file.
+type file struct {
+	Entries map[string]Entry `json:"entries"`
+}
+
+// Store holds values across invocations. It is safe for concurrent use.
+type Store struct {
+	// refresh ignores the stored values, new values are still stored.
+	refresh bool
+
+	mu sync.Mutex
+	f  *config.Store[file]
+}
+
+// Open loads the cache stored in the gh-wrun cache directory.
+// If refresh is true, the stored values are not used.
+func Open(refresh bool) (*Store, error) {
+	p, err := config.CachePath(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return OpenFile(p, refresh)
+}
+
+// OpenFile loads the cache stored in the file at path.
+func OpenFile(path string, refresh bool) (*Store, error) {
+	f, err := config.OpenStore[file](path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading cache: %w", err)
+	}
+
+	if f.Data.Entries == nil {
+		f.Data.Entries = map[string]Entry{}
+	}
+	return &Store{refresh: refresh, f: f}, nil
+}
+
+// Get decodes the value of key into v if it was stored less than ttl ago.
+// A zero ttl never expires. It reports whether v was set.
+func (s *Store) Get(key string, ttl time.Duration, v any) bool {
+	if s.refresh {
+		return false
+	}
+
+	s.mu.Lock()
+	e, ok := s.f.Data.Entries[key]
+	s.mu.Unlock()
+
+	if !ok || (ttl > 0 && time.Since(e.Time) > ttl) {
+		return false
+	}
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// Put stores v as the value of key.
+func (s *Store) Put(key string, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.f.Data.Entries[key] = Entry{Value: b, Time: time.Now()}
+}
+
+// Save writes the cache back to the file it was loaded from,
+// dropping the entries older than maxAge.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, e := range s.f.Data.Entries {
+		if time.Since(e.Time) > maxAge {
+			delete(s.f.Data.Entries, k)
+		}
+	}
+	return s.f.Save()
+}
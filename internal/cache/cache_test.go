@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	s, err := OpenFile(path, false)
+	if err != nil {
+		t.Fatalf("Error opening cache: %s\n", err)
+	}
+
+	s.Put("workflows", []string{"build.yml", "deploy.yml"})
+	s.Put("version", "2.40.0")
+	s.f.Data.Entries["expired"] = Entry{Value: []byte(`"old"`), Time: time.Now().Add(-time.Hour)}
+	s.f.Data.Entries["dropped"] = Entry{Value: []byte(`"old"`), Time: time.Now().Add(-maxAge - time.Hour)}
+
+	if err := s.Save(); err != nil {
+		t.Fatalf("Error saving cache: %s\n", err)
+	}
+
+	var workflows []string
+	if !s.Get("workflows", time.Minute, &workflows) {
+		t.Errorf("Expected a cached value but got none\n")
+	}
+	if want := []string{"build.yml", "deploy.yml"}; !reflect.DeepEqual(workflows, want) {
+		t.Errorf("Expected is %v but got %v\n", want, workflows)
+	}
+
+	var v string
+	if s.Get("expired", time.Minute, &v) {
+		t.Errorf("Expected no value for an expired entry but got %q\n", v)
+	}
+	if !s.Get("expired", 0, &v) {
+		t.Errorf("Expected a value without TTL but got none\n")
+	}
+	if _, ok := s.f.Data.Entries["dropped"]; ok {
+		t.Errorf("Expected entries older than the max age to be dropped\n")
+	}
+
+	// refresh ignores the stored values
+	s, err = OpenFile(path, true)
+	if err != nil {
+		t.Fatalf("Error reopening cache: %s\n", err)
+	}
+	if s.Get("version", 0, &v) {
+		t.Errorf("Expected no value on refresh but got %q\n", v)
+	}
+}
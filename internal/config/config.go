@@ -0,0 +1,72 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const appName = "gh-wrun"
+
+// Dir returns the directory where gh-wrun stores its files.
+// It is $XDG_CONFIG_HOME/gh-wrun or the platform equivalent.
+func Dir() (string, error) {
+	d, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, appName), nil
+}
+
+// Path returns the path of the named file in the gh-wrun directory.
+func Path(name string) (string, error) {
+	d, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, name), nil
+}
+
+// CachePath returns the path of the named file in the gh-wrun cache directory.
+// It is $XDG_CACHE_HOME/gh-wrun or the platform equivalent.
+func CachePath(name string) (string, error) {
+	d, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, appName, name), nil
+}
+
+// readJSON reads the JSON file at path into v.
+// A missing file is not an error and leaves v untouched.
+func readJSON(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// writeJSON writes v to the JSON file at path, creating the directory if needed.
+func writeJSON(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that a failure does not corrupt the file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,23 @@
+package config
+
+// Store is a JSON file loaded into Data and written back by Save.
+// It is not safe for concurrent use.
+type Store[T any] struct {
+	path string
+	// Data is the content of the file, the zero value if there is no file yet.
+	Data T
+}
+
+// OpenStore loads the JSON file at path.
+func OpenStore[T any](path string) (*Store[T], error) {
+	s := &Store[T]{path: path}
+	if err := readJSON(path, &s.Data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes Data back to the file it was loaded from.
+func (s *Store[T]) Save() error {
+	return writeJSON(s.path, s.Data)
+}
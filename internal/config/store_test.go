@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	type data struct {
+		Names []string `json:"names"`
+	}
+	path := filepath.Join(t.TempDir(), "gh-wrun", "store.json")
+
+	// a missing file is empty
+	s, err := OpenStore[data](path)
+	if err != nil {
+		t.Fatalf("Error opening store: %s\n", err)
+	}
+	if !reflect.DeepEqual(s.Data, data{}) {
+		t.Errorf("Expected is %v but got %v\n", data{}, s.Data)
+	}
+
+	s.Data.Names = []string{"build", "deploy"}
+	if err := s.Save(); err != nil {
+		t.Fatalf("Error saving store: %s\n", err)
+	}
+
+	s, err = OpenStore[data](path)
+	if err != nil {
+		t.Fatalf("Error reopening store: %s\n", err)
+	}
+	if want := []string{"build", "deploy"}; !reflect.DeepEqual(s.Data.Names, want) {
+		t.Errorf("Expected is %v but got %v\n", want, s.Data.Names)
+	}
+
+	// an invalid file is an error
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("Error writing file: %s\n", err)
+	}
+	if _, err := OpenStore[data](path); err == nil {
+		t.Errorf("Expected error but got nil\n")
+	}
+}
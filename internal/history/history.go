@@ -0,0 +1,91 @@
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/t4kamura/gh-wrun/internal/config"
+)
+
+const (
+	fileName = "history.json"
+	// maxEntries is the number of entries kept in the history.
+	maxEntries = 200
+)
+
+// Entry is a confirmed dispatch of a workflow.
+type Entry struct {
+	Repo         string                        `json:"repo"`
+	Branch       string                        `json:"branch"`
+	WorkflowId   string                        `json:"workflowId"`
+	WorkflowPath string                        `json:"workflowPath"`
+	Inputs       []struct{ Key, Value string } `json:"inputs"`
+	// InputNames are the inputs the workflow defined at the dispatch,
+	// including those left to their defaults. Older entries do not have them.
+	InputNames []string  `json:"inputNames,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// file is the content of the history file, oldest entry first.
+type file struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Store holds the history of every repository.
+type Store struct {
+	*config.Store[file]
+}
+
+// Open loads the history stored in the gh-wrun config directory.
+func Open() (*Store, error) {
+	p, err := config.Path(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return OpenFile(p)
+}
+
+// OpenFile loads the history stored in the file at path.
+func OpenFile(path string) (*Store, error) {
+	s, err := config.OpenStore[file](path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading history: %w", err)
+	}
+	return &Store{s}, nil
+}
+
+// Add appends e to the history, dropping the oldest entries over the limit.
+func (s *Store) Add(e Entry) {
+	s.Data.Entries = append(s.Data.Entries, e)
+	if len(s.Data.Entries) > maxEntries {
+		s.Data.Entries = s.Data.Entries[len(s.Data.Entries)-maxEntries:]
+	}
+}
+
+// ForRepo returns the entries of repo, newest first.
+func (s *Store) ForRepo(repo string) []Entry {
+	entries := []Entry{}
+	for i := len(s.Data.Entries) - 1; i >= 0; i-- {
+		if s.Data.Entries[i].Repo == repo {
+			entries = append(entries, s.Data.Entries[i])
+		}
+	}
+	return entries
+}
+
+// String returns a one-line description of the entry for pickers.
+func (e Entry) String() string {
+	inputs := []string{}
+	for _, i := range e.Inputs {
+		inputs = append(inputs, i.Key+"="+i.Value)
+	}
+
+	return fmt.Sprintf("%s %s on %s %s",
+		e.Time.Local().Format("2006-01-02 15:04"),
+		filepath.Base(e.WorkflowPath),
+		e.Branch,
+		strings.Join(inputs, " "),
+	)
+}
@@ -0,0 +1,57 @@
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAdd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	s, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("Error opening history: %s\n", err)
+	}
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < maxEntries+2; i++ {
+		repo := "t4kamura/gh-wrun"
+		if i%2 == 1 {
+			repo = "t4kamura/other"
+		}
+		s.Add(Entry{
+			Repo:         repo,
+			Branch:       "main",
+			WorkflowId:   "1",
+			WorkflowPath: ".github/workflows/deploy.yml",
+			Inputs:       []struct{ Key, Value string }{{Key: "env", Value: "prod"}},
+			Time:         base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	if len(s.Data.Entries) != maxEntries {
+		t.Errorf("Expected is %d entries but got %d\n", maxEntries, len(s.Data.Entries))
+	}
+
+	got := s.ForRepo("t4kamura/gh-wrun")
+	if len(got) != maxEntries/2 {
+		t.Fatalf("Expected is %d entries but got %d\n", maxEntries/2, len(got))
+	}
+
+	// newest first, and the two oldest entries are dropped
+	wantFirst := base.Add(time.Duration(maxEntries) * time.Minute)
+	if !got[0].Time.Equal(wantFirst) {
+		t.Errorf("Expected is %v but got %v\n", wantFirst, got[0].Time)
+	}
+
+	wantLast := base.Add(2 * time.Minute)
+	if !got[len(got)-1].Time.Equal(wantLast) {
+		t.Errorf("Expected is %v but got %v\n", wantLast, got[len(got)-1].Time)
+	}
+
+	if !reflect.DeepEqual(s.ForRepo("t4kamura/unknown"), []Entry{}) {
+		t.Errorf("Expected no entries for unknown repo\n")
+	}
+}
@@ -0,0 +1,63 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+// maxConcurrentLoads is the number of workflow files loaded at once.
+const maxConcurrentLoads = 8
+
+// undispatchableReasons loads the triggers of the workflows on ref concurrently
+// and returns why each one cannot be dispatched, or an empty string if it can.
+// A workflow whose file cannot be loaded is assumed to be dispatchable.
+func (r *InputResult) undispatchableReasons(ctx context.Context, workflows []subproc.GhWorkflow, ref string) []string {
+	reasons := make([]string, len(workflows))
+	sem := make(chan struct{}, maxConcurrentLoads)
+
+	var wg sync.WaitGroup
+	for i, w := range workflows {
+		wg.Add(1)
+		go func(i int, w subproc.GhWorkflow) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			reasons[i] = r.undispatchableReason(ctx, w, ref)
+		}(i, w)
+	}
+	wg.Wait()
+
+	return reasons
+}
+
+// undispatchableReason returns why the workflow cannot be dispatched on ref.
+// Like the inputs, the triggers fall back to the default branch
+// when the workflow does not exist on ref.
+func (r *InputResult) undispatchableReason(ctx context.Context, w subproc.GhWorkflow, ref string) string {
+	var parseErr *subproc.WorkflowParseError
+
+	events, err := subproc.GetWorkflowEvents(ctx, r.client, w, ref)
+	if ref != "" && errors.Is(err, subproc.ErrNotFound) {
+		events, err = subproc.GetWorkflowEvents(ctx, r.client, w, "")
+	}
+
+	if errors.As(err, &parseErr) {
+		return parseErr.Error()
+	} else if err != nil {
+		return ""
+	}
+
+	if subproc.IsDispatchable(events) {
+		return ""
+	}
+	if len(events) == 0 {
+		return "no trigger"
+	}
+	return fmt.Sprintf("no workflow_dispatch trigger, runs on %s", strings.Join(events, ", "))
+}
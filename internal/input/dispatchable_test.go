@@ -0,0 +1,42 @@
+package input
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+func TestUndispatchableReasons(t *testing.T) {
+	workflows := []subproc.GhWorkflow{
+		{Id: "1", Name: "Deploy", Path: ".github/workflows/deploy.yml"},
+		{Id: "2", Name: "Nightly", Path: ".github/workflows/nightly.yml"},
+		{Id: "3", Name: "Broken", Path: ".github/workflows/broken.yml"},
+		{Id: "4", Name: "Release", Path: ".github/workflows/release.yml"},
+		{Id: "5", Name: "Unknown", Path: ".github/workflows/unknown.yml"},
+	}
+	client := &subproc.FakeClient{
+		Files: map[subproc.FakeFileKey][]byte{
+			{Path: ".github/workflows/deploy.yml", Ref: "main"}:  []byte("on: [push, workflow_dispatch]\n"),
+			{Path: ".github/workflows/nightly.yml", Ref: "main"}: []byte("on:\n  schedule:\n    - cron: '0 3 * * *'\n  push:\n"),
+			{Path: ".github/workflows/broken.yml", Ref: "main"}:  []byte("on: [push\n"),
+			// only on the default branch
+			{Path: ".github/workflows/release.yml", Ref: ""}: []byte("on: workflow_dispatch\n"),
+		},
+	}
+
+	r := &InputResult{client: client}
+	got := r.undispatchableReasons(context.Background(), workflows, "main")
+
+	want := []string{
+		"",
+		"no workflow_dispatch trigger, runs on schedule, push",
+		"invalid workflow file, line 1: did not find expected ',' or ']'",
+		"",
+		"",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected is %q but got %q\n", want, got)
+	}
+}
@@ -1,65 +1,221 @@
 package input
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/t4kamura/gh-wrun/internal/interactive"
+	"github.com/t4kamura/gh-wrun/internal/progress"
 	"github.com/t4kamura/gh-wrun/internal/subproc"
 	"github.com/t4kamura/gh-wrun/internal/table"
+	ver "github.com/t4kamura/gh-wrun/internal/version"
 )
 
+// ErrCanceled is returned when the user declines to go on.
+var ErrCanceled = errors.New("Canceled")
+
 type InputResult struct {
 	Branch         string
 	Workflow       subproc.GhWorkflow
 	WorkflowInputs []struct{ Key, Value string }
-	IsRun          bool
+	// WorkflowInputNames are the names of all the inputs the workflow defines on Branch,
+	// including those left to their defaults.
+	WorkflowInputNames []string
+	IsRun              bool
+	// EnableWorkflow is true when the disabled workflow must be enabled before the dispatch.
+	EnableWorkflow bool
+
+	// client is the access to GitHub.
+	client subproc.Client
+	// out is where the tables are rendered.
+	out io.Writer
+	// presetInputs are the input values of the preset in use, keyed by name.
+	presetInputs map[string]string
+	// prefetch loads data in the background, nil if disabled.
+	prefetch *prefetcher
+}
+
+// Options holds the values given from the command line.
+// Any value left empty is asked interactively.
+type Options struct {
+	// BranchAuto uses the current branch without asking.
+	BranchAuto bool
+	// Ref is the git ref to run the workflow on.
+	Ref string
+	// Workflow is the workflow name, file name or ID.
+	Workflow string
+	// Inputs are the workflow inputs given in advance.
+	Inputs []struct{ Key, Value string }
+	// Yes skips the confirmation.
+	Yes bool
+	// Interactive allows prompting the user for missing values.
+	Interactive bool
+	// Preset is the name of the preset that pre-fills the answers.
+	Preset string
+	// Repo is the target repository, the zero value is the current directory's one.
+	Repo subproc.Repo
+	// Remote is the git remote to list branches from.
+	Remote string
+	// All lists the disabled workflows too.
+	All bool
+	// ShowUndispatchable lists the workflows without a workflow_dispatch trigger too,
+	// greyed out with the reason they cannot be run.
+	ShowUndispatchable bool
+	// Out is where the tables of the answers are rendered, stdout if nil.
+	Out io.Writer
+	// Client is the access to GitHub, gh subcommands against Repo if nil.
+	Client subproc.Client
+	// Prefetch loads the workflows, environments and workflow files in the background
+	// while the user answers. Client must remember what it got, see subproc.CachingClient.
+	Prefetch bool
+	// DryRun only collects the answers, the branch is not pushed.
+	DryRun bool
+	// Replay marks Inputs as answers of an earlier dispatch.
+	// Inputs removed from the workflow since are dropped,
+	// and inputs added since are asked.
+	Replay bool
+	// ReplayInputNames are the inputs the workflow defined at the earlier dispatch.
+	// If nil, the inputs missing from Inputs are taken as added since.
+	ReplayInputNames []string
 }
 
 // NewInputResult asks the user to all the required inputs to run a workflow.
+// Values already given in opts are used as is and are not asked.
 // The answers are stored in InputResult receiver.
-func NewInputResult(branchAuto bool) (*InputResult, error) {
-	r := &InputResult{}
-
-	if err := r.askBranch(branchAuto); err != nil {
-		return r, err
-	} else if err := r.askWorkflow(); err != nil {
-		return r, err
-	} else if err := r.askWorkflowInputs(); err != nil {
-		return r, err
+// ErrCanceled is returned when the user interrupts a prompt or ctx is canceled.
+func NewInputResult(ctx context.Context, opts Options) (*InputResult, error) {
+	r := &InputResult{client: opts.Client, out: opts.Out}
+	if r.client == nil {
+		r.client = subproc.NewGhClient(opts.Repo)
+	}
+	if r.out == nil {
+		r.out = os.Stdout
+	}
+
+	// background work is stopped once the answers are collected or aborted
+	askCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if opts.Prefetch {
+		r.prefetch = newPrefetcher(askCtx, r.client)
+		r.prefetch.workflows(opts.All)
+	}
+
+	err := r.ask(askCtx, opts)
+	if err != nil && (errors.Is(err, interactive.ErrInterrupted) || ctx.Err() != nil) {
+		return r, ErrCanceled
+	}
+	return r, err
+}
+
+// ask asks the inputs in order, see NewInputResult.
+func (r *InputResult) ask(ctx context.Context, opts Options) error {
+	if opts.Preset != "" {
+		var err error
+		if opts, err = r.applyPreset(ctx, opts); err != nil {
+			return err
+		}
+	}
+
+	if err := r.askBranch(ctx, opts); err != nil {
+		return err
+	} else if err := r.checkBranchPushed(ctx, opts); err != nil {
+		return err
+	} else if err := r.askWorkflow(ctx, opts); err != nil {
+		return err
+	} else if err := r.askEnableWorkflow(ctx, opts); err != nil {
+		return err
+	} else if err := r.askWorkflowInputs(ctx, opts); err != nil {
+		return err
+	} else if opts.Yes {
+		r.IsRun = true
+	} else if !opts.Interactive {
+		return errors.New("Confirmation required, use --yes to run without prompting")
 	} else {
 		r.askRunWithRenderTable()
+		if r.IsRun {
+			r.askSavePreset(ctx, opts.Preset)
+		}
 	}
 
-	return r, nil
+	return nil
 }
 
-// AskBranch asks the user to select a branch.
+// AskBranch asks the user to select a ref, either a branch or a tag.
 // The answer is stored in InputResult receiver.
+// If a ref is given, it is used as is.
 // If the auto flag is true, automatically set the current branch
-func (r *InputResult) askBranch(auto bool) error {
-	currentBranch, err := subproc.GetBranchName()
+// For a repository other than the local checkout, the default branch
+// stands for the current branch and refs come from the GitHub API.
+func (r *InputResult) askBranch(ctx context.Context, opts Options) error {
+	if opts.Ref != "" {
+		r.Branch = opts.Ref
+		return nil
+	}
+
+	auto := opts.BranchAuto || !opts.Interactive
+
+	var (
+		currentBranch string
+		err           error
+	)
+	if opts.Repo.IsLocal() {
+		currentBranch, err = currentLocalRef(ctx)
+	} else {
+		currentBranch, err = subproc.GetDefaultBranch(ctx, opts.Repo)
+	}
 	if err != nil {
 		return err
 	}
 
 	if auto {
+		if currentBranch == "" {
+			return errors.New("HEAD is detached, use -b or --ref to choose a branch or tag")
+		}
 		r.Branch = currentBranch
 		return nil
 	}
 
-	rBranches, err := subproc.GetRemoteBranches()
-	if err != nil {
-		return err
+	var rBranches, tags []string
+	if opts.Repo.IsLocal() {
+		remote, err := askRemote(ctx, opts)
+		if err != nil {
+			return err
+		}
+		rBranches, err = subproc.GetRemoteBranches(ctx, remote)
+		if err != nil {
+			return err
+		}
+		tags, err = subproc.GetRemoteTags(ctx, remote)
+		if err != nil {
+			warnf("Failed to list tags: %s", err)
+		}
+	} else {
+		rBranches, err = subproc.GetRepoBranches(ctx, opts.Repo)
+		if err != nil {
+			return err
+		}
+		tags, err = subproc.GetRepoTags(ctx, opts.Repo)
+		if err != nil {
+			warnf("Failed to list tags: %s", err)
+		}
 	}
 
-	if len(rBranches) == 0 {
+	if len(rBranches) == 0 && len(tags) == 0 {
 		return errors.New("No remote branches found")
 	}
 
-	if len(rBranches) == 1 && rBranches[0] == currentBranch {
+	refs := orderRefs(currentBranch, rBranches, ver.SortTags(tags))
+
+	if len(refs) == 1 && refs[0] == currentBranch {
 		answer := interactive.AskConfirm("Run on this branch: " + currentBranch)
 		if !answer {
 			return errors.New("No other executable branch found")
@@ -68,77 +224,246 @@ func (r *InputResult) askBranch(auto bool) error {
 		return nil
 	}
 
-	// order rBranches so that currentBranch is at the top
-	for i, b := range rBranches {
-		if b == currentBranch {
-			// remove currentBranch from rBranches
-			rBranches = rBranches[:i+copy(rBranches[i:], rBranches[i+1:])]
+	defaultRef := slices.Index(refs, currentBranch)
+	if last := slices.Index(refs, loadLastSelection(ctx, opts.Repo, lastSelectionRef)); last >= 0 {
+		defaultRef = last
+	}
 
-			// append currentBranch to the top
-			rBranches = append([]string{currentBranch}, rBranches...)
-		}
+	choices := []interactive.Choice{}
+	for _, ref := range refs {
+		choices = append(choices, interactive.Choice{Label: ref})
 	}
 
-	answer, err := interactive.AskChoices("Select a branch", rBranches, currentBranch)
+	i, err := interactive.AskSearch("Select a branch or tag", choices, defaultRef)
 	if err != nil {
 		return err
 	}
 
-	r.Branch = answer
+	r.Branch = refs[i]
+	saveLastSelection(ctx, opts.Repo, lastSelectionRef, r.Branch)
 
 	return nil
 }
 
+// recentTagCount is the number of newest tags listed right after the current branch.
+const recentTagCount = 3
+
+// orderRefs returns the refs to choose from in the order of the picker:
+// the current branch, the newest tags, the other branches and the remaining tags.
+// The tags must be sorted from the newest one. Duplicated names are listed once.
+func orderRefs(currentBranch string, branches, tags []string) []string {
+	recent := tags[:min(recentTagCount, len(tags))]
+	older := tags[len(recent):]
+
+	refs := []string{}
+	seen := map[string]bool{}
+	add := func(names ...string) {
+		for _, n := range names {
+			if !seen[n] {
+				seen[n] = true
+				refs = append(refs, n)
+			}
+		}
+	}
+
+	for _, b := range branches {
+		if b == currentBranch {
+			add(currentBranch)
+		}
+	}
+	add(recent...)
+	add(branches...)
+	add(older...)
+
+	return refs
+}
+
+// allRemotes is the remote choice listing the branches of every remote.
+const allRemotes = "(all remotes)"
+
+// askRemote asks the user to select the git remote to list branches from.
+// The cursor is on the remote pointing to the target repository.
+// It is not asked when a remote is given or there is only one.
+// An empty result means all remotes.
+func askRemote(ctx context.Context, opts Options) (string, error) {
+	if opts.Remote != "" {
+		return opts.Remote, nil
+	}
+
+	remotes, err := subproc.GetRemotes(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(remotes) <= 1 {
+		return "", nil
+	}
+
+	answer, err := interactive.AskChoices("Select a remote", append(remotes, allRemotes), defaultRemote(ctx, opts, remotes))
+	if err != nil {
+		return "", err
+	}
+
+	if answer == allRemotes {
+		return "", nil
+	}
+	return answer, nil
+}
+
+// defaultRemote returns the remote among remotes pointing to the target repository,
+// else the only remote, else origin.
+func defaultRemote(ctx context.Context, opts Options, remotes []string) string {
+	if repo, err := subproc.GetRepositoryWithOwner(ctx, opts.Repo); err == nil {
+		if remote, err := subproc.FindRemote(ctx, repo); err == nil && remote != "" {
+			return remote
+		}
+	}
+
+	if len(remotes) == 1 {
+		return remotes[0]
+	}
+	return "origin"
+}
+
 // selectWorkflow asks the user to select a workflow.
 // If there is only one workflow, it ask ok or cancel.
+// If a workflow is given, it is looked up by name, file name or ID instead.
 // The answer is stored in InputResult receiver.
-func (r *InputResult) askWorkflow() error {
+func (r *InputResult) askWorkflow(ctx context.Context, opts Options) error {
 	var selectedWorkflow subproc.GhWorkflow
-	workflows, err := subproc.GetWorkflows()
+	workflows, err := r.client.ListWorkflows(ctx, opts.All)
 	if err != nil {
 		return err
 	}
 
 	if len(workflows) == 0 {
-		return errors.New("No active workflows found")
+		if opts.All {
+			return errors.New("No workflows found")
+		}
+		return errors.New("No active workflows found, use --all to list disabled workflows")
+	}
+
+	if opts.Workflow != "" {
+		w, err := subproc.FindWorkflow(workflows, opts.Workflow)
+		if err != nil {
+			return err
+		}
+
+		// the inputs are compared with the default branch
+		r.prefetch.workflowFile(w, "")
+
+		if reason := r.undispatchableReason(ctx, w, r.Branch); reason != "" {
+			return fmt.Errorf("Workflow %q cannot be run: %s", w.Name, reason)
+		}
+
+		r.Workflow = w
+		return nil
+	}
+
+	if !opts.Interactive {
+		return errors.New("No workflow specified, use --workflow when not running in a terminal")
+	}
+
+	// only dispatchable workflows are listed, the others follow greyed out if asked
+	unavailable := map[json.Number]string{}
+	dispatchable, undispatchable := []subproc.GhWorkflow{}, []subproc.GhWorkflow{}
+	for i, reason := range r.undispatchableReasons(ctx, workflows, r.Branch) {
+		if reason == "" {
+			dispatchable = append(dispatchable, workflows[i])
+		} else {
+			unavailable[workflows[i].Id] = reason
+			undispatchable = append(undispatchable, workflows[i])
+		}
+	}
+
+	if len(dispatchable) == 0 && !opts.ShowUndispatchable {
+		return errors.New("No workflows with a workflow_dispatch trigger found, use --show-undispatchable to list the others")
+	}
+
+	workflows = dispatchable
+	if opts.ShowUndispatchable {
+		workflows = append(workflows, undispatchable...)
 	}
 
 	workflowNames := []string{}
 	for _, workflow := range workflows {
-		workflowNames = append(workflowNames, workflow.Name)
+		workflowNames = append(workflowNames, workflowLabel(workflow))
 	}
 
-	if len(workflowNames) == 1 {
+	if len(workflowNames) == 1 && len(dispatchable) == 1 {
 		ok := interactive.AskConfirm(fmt.Sprintf("Do you want to run [%s]", workflowNames[0]))
 		if !ok {
-			return errors.New("Canceled")
+			return ErrCanceled
 		}
 
 		r.Workflow = workflows[0]
 		return nil
 	}
 
-	selectedWorkflowName, err := interactive.AskChoices("Select the workflow you wish to run", workflowNames, workflowNames[0])
+	// the last workflow is remembered by path, names are not unique
+	defaultWorkflow := 0
+	last := loadLastSelection(ctx, opts.Repo, lastSelectionWorkflow)
+	choices := []interactive.Choice{}
+	for i, w := range workflows {
+		w := w
+		if w.Path == last {
+			defaultWorkflow = i
+		}
+		choices = append(choices, interactive.Choice{
+			Label:       workflowLabel(w),
+			Keywords:    []string{w.Path},
+			Unavailable: unavailable[w.Id],
+			// the file on ref is already loaded, the inputs are compared with the default branch
+			OnHighlight: func() { r.prefetch.workflowFile(w, "") },
+		})
+	}
+
+	i, err := interactive.AskSearch("Select the workflow you wish to run", choices, defaultWorkflow)
 	if err != nil {
 		return err
 	}
+	selectedWorkflow = workflows[i]
 
-	for _, w := range workflows {
-		if w.Name == selectedWorkflowName {
-			selectedWorkflow = w
-		}
+	if reason := unavailable[selectedWorkflow.Id]; reason != "" {
+		return fmt.Errorf("Workflow %q cannot be run: %s", selectedWorkflow.Name, reason)
 	}
 
-	if selectedWorkflow.Name == "" {
-		return errors.New("No workflow found")
+	r.Workflow = selectedWorkflow
+	saveLastSelection(ctx, opts.Repo, lastSelectionWorkflow, selectedWorkflow.Path)
+	return nil
+}
+
+// workflowLabel returns the name of the workflow in pickers,
+// disabled workflows are marked as such.
+func workflowLabel(w subproc.GhWorkflow) string {
+	if w.IsActive() {
+		return w.Name
 	}
+	return fmt.Sprintf("%s (%s)", w.Name, strings.ReplaceAll(w.Status, "_", " "))
+}
 
-	r.Workflow = selectedWorkflow
+// askEnableWorkflow asks the user to enable the selected workflow if it is disabled.
+// The workflow is only enabled right before the dispatch, see EnableWorkflow.
+func (r *InputResult) askEnableWorkflow(ctx context.Context, opts Options) error {
+	if r.Workflow.IsActive() {
+		return nil
+	}
+
+	if !opts.Interactive {
+		return fmt.Errorf("Workflow %q is disabled, enable it with gh workflow enable %s", r.Workflow.Name, r.Workflow.Id)
+	}
+
+	if !interactive.AskConfirm(fmt.Sprintf("Workflow [%s] is disabled, enable it", r.Workflow.Name)) {
+		return ErrCanceled
+	}
+
+	r.EnableWorkflow = true
 	return nil
 }
 
 // askWorkflowInputs asks workflow inputs to user.
-func (r *InputResult) askWorkflowInputs() error {
+// Inputs given in opts are validated and used without asking.
+func (r *InputResult) askWorkflowInputs(ctx context.Context, opts Options) error {
 	var err error
 	answers := []struct{ Key, Value string }{}
 
@@ -146,40 +471,96 @@ func (r *InputResult) askWorkflowInputs() error {
 		return errors.New("No workflow found. Need to run AskWorkflow() before AskWorkflowInputs()")
 	}
 
-	w, err := r.Workflow.GetWorkflowInputs()
+	w, err := r.loadWorkflowInputs(ctx)
 	if err != nil {
 		return err
 	}
 
+	givenInputs := opts.Inputs
+	added := map[string]bool{}
+	if opts.Replay {
+		var removed []string
+		givenInputs, removed, added = diffReplayInputs(w, opts.Inputs, opts.ReplayInputNames)
+		for _, k := range removed {
+			warnf("Input %q has been removed from the workflow since, ignored", k)
+		}
+		for k := range added {
+			warnf("Input %q has been added to the workflow since", k)
+		}
+	}
+
+	given, err := matchGivenInputs(w, givenInputs)
+	if err != nil {
+		return err
+	}
+
+	r.dropUnknownPresetInputs(w)
+
 	for _, v := range w {
+		if value, ok := given[v.Name]; ok {
+			answers = append(answers, struct{ Key, Value string }{
+				Key:   v.Name,
+				Value: value,
+			})
+			continue
+		}
+
+		defaultValue := v.Default
+		if value, ok := r.presetInputs[v.Name]; ok {
+			if !opts.Interactive {
+				if err := validateInputValue(v, value); err != nil {
+					return err
+				}
+				answers = append(answers, struct{ Key, Value string }{
+					Key:   v.Name,
+					Value: value,
+				})
+				continue
+			}
+			defaultValue = value
+		}
+
+		if !opts.Interactive {
+			if v.Required && v.Default == "" {
+				return fmt.Errorf("Input %q is required, use --input %s=VALUE", v.Name, v.Name)
+			}
+			continue
+		}
+
 		message := v.Description
 		if message == "" {
 			message = v.Name
 		}
+		if added[v.Name] {
+			message = interactive.Highlight("[new]") + " " + message
+		}
 
 		var answer string
 		switch v.Type {
 		case subproc.GhWorkflowInputTypeChoice:
-			answer, err = interactive.AskChoices(message, v.Options, v.Options[0])
+			if len(v.Options) == 0 {
+				return fmt.Errorf("Input %q of type choice has no options", v.Name)
+			}
+			answer, err = interactive.AskChoices(message, v.Options, r.defaultChoice(v, v.Options))
 		case subproc.GhWorkflowInputTypeBoolean:
 			var ok bool
-			d, _ := strconv.ParseBool(v.Default)
+			d, _ := strconv.ParseBool(defaultValue)
 			ok, err = interactive.AskBool(message, d)
 			answer = strconv.FormatBool(ok)
 		case subproc.GhWorkflowInputTypeEnvironment:
-			envs, err := subproc.GetEnvironments()
+			envs, err := r.client.ListEnvironments(ctx)
 			if err != nil {
 				return err
 			}
 			if len(envs) == 0 {
 				return fmt.Errorf("no environments exist")
 			}
-			answer, err = interactive.AskChoices(message, envs, envs[0])
+			answer, err = interactive.AskChoices(message, envs, r.defaultChoice(v, envs))
 			if err != nil {
 				return err
 			}
 		default:
-			answer, err = interactive.AskInput(message, v.Default)
+			answer, err = interactive.AskInput(message, defaultValue, inputValidator(v))
 		}
 
 		if err != nil {
@@ -192,20 +573,124 @@ func (r *InputResult) askWorkflowInputs() error {
 		})
 	}
 
+	if err := validateAnswers(w, answers); err != nil {
+		return err
+	}
+
 	r.WorkflowInputs = answers
+	r.WorkflowInputNames = []string{}
+	for _, v := range w {
+		r.WorkflowInputNames = append(r.WorkflowInputNames, v.Name)
+	}
 	return nil
 }
 
+// defaultChoice returns the choice the cursor starts on for the input:
+// the preset value, then the declared default, then the first choice.
+// It warns when the declared default is not among the choices.
+func (r *InputResult) defaultChoice(v subproc.GhWorkflowInput, choices []string) string {
+	if value, ok := r.presetInputs[v.Name]; ok && slices.Contains(choices, value) {
+		return value
+	}
+
+	if v.Default != "" {
+		if slices.Contains(choices, v.Default) {
+			return v.Default
+		}
+		warnf("Default %q of input %q is not among %v, selecting %q", v.Default, v.Name, choices, choices[0])
+	}
+
+	return choices[0]
+}
+
+// loadWorkflowInputs returns the workflow inputs defined on the selected branch.
+// It warns when the workflow file is missing on the branch or its inputs
+// differ from the default branch. Other errors on the branch, like an invalid file, are returned.
+func (r *InputResult) loadWorkflowInputs(ctx context.Context) ([]subproc.GhWorkflowInput, error) {
+	w, err := subproc.GetWorkflowInputs(ctx, r.client, r.Workflow, r.Branch)
+	if errors.Is(err, subproc.ErrNotFound) {
+		d, dErr := subproc.GetWorkflowInputs(ctx, r.client, r.Workflow, "")
+		if dErr != nil {
+			return nil, err
+		}
+		warnf("Workflow %s does not exist on %s, using inputs from the default branch", r.Workflow.Path, r.Branch)
+		return d, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	d, err := subproc.GetWorkflowInputs(ctx, r.client, r.Workflow, "")
+	if err == nil && !reflect.DeepEqual(w, d) {
+		warnf("Inputs of workflow %s on %s differ from the default branch", r.Workflow.Path, r.Branch)
+	}
+
+	return w, nil
+}
+
+// warnf prints a warning message to stderr.
+func warnf(format string, a ...any) {
+	defer progress.Suspend()()
+	fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", a...)
+}
+
+// diffReplayInputs compares the inputs of an earlier dispatch with the current workflow inputs.
+// known are the inputs the workflow defined at the dispatch, nil if unknown.
+// It returns the given inputs still defined, the names of those removed since
+// and the names of the workflow inputs added since.
+func diffReplayInputs(inputs []subproc.GhWorkflowInput, given []struct{ Key, Value string }, known []string) (kept []struct{ Key, Value string }, removed []string, added map[string]bool) {
+	defined := map[string]bool{}
+	for _, v := range inputs {
+		defined[v.Name] = true
+	}
+
+	seen := map[string]bool{}
+	for _, g := range given {
+		if defined[g.Key] {
+			kept = append(kept, g)
+			seen[g.Key] = true
+		} else {
+			removed = append(removed, g.Key)
+		}
+	}
+
+	// inputs left to their defaults are not given, but were known
+	for _, k := range known {
+		seen[k] = true
+	}
+
+	added = map[string]bool{}
+	for k := range defined {
+		if !seen[k] {
+			added[k] = true
+		}
+	}
+
+	return kept, removed, added
+}
+
 // AskRun asks the user to confirm the execution.
 // Render the table and ask if it is ok to run.
 // The answer is stored in InputResult receiver.
 func (r *InputResult) askRunWithRenderTable() {
-	table.Render(r.genTableData())
+	table.Render(r.out, r.genTableData())
 	answer := interactive.AskConfirm("Run this?")
 
 	r.IsRun = answer
 }
 
+// RenderRunTable renders the table of the answers with the created run.
+func (r *InputResult) RenderRunTable(runNumber int, runUrl string) {
+	table.Render(r.out, r.genRunTableData(runNumber, runUrl))
+}
+
+// genRunTableData generates table data from InputResult receiver and the created run.
+func (r *InputResult) genRunTableData(runNumber int, runUrl string) [][]string {
+	return append(r.genTableData(),
+		[]string{"Run", "Number", "#" + strconv.Itoa(runNumber)},
+		[]string{"Run", "URL", runUrl},
+	)
+}
+
 // genTableData generates table data from InputResult receiver.
 // It is used to render the table.
 func (r *InputResult) genTableData() [][]string {
@@ -1,7 +1,11 @@
 package input
 
 import (
+	"context"
+	"errors"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/t4kamura/gh-wrun/internal/subproc"
@@ -32,4 +36,275 @@ func TestGenTableData(t *testing.T) {
 	if !reflect.DeepEqual(result, want) {
 		t.Errorf("Expected is %v but got %v\n", want, result)
 	}
+
+	want = append(want,
+		[]string{"Run", "Number", "#42"},
+		[]string{"Run", "URL", "https://github.com/t4kamura/gh-wrun/actions/runs/1"},
+	)
+
+	result = input.genRunTableData(42, "https://github.com/t4kamura/gh-wrun/actions/runs/1")
+
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Expected is %v but got %v\n", want, result)
+	}
+}
+
+func TestDiffReplayInputs(t *testing.T) {
+	inputs := []subproc.GhWorkflowInput{
+		{Name: "env"},
+		{Name: "message"},
+		{Name: "region"},
+	}
+	given := []struct{ Key, Value string }{
+		{Key: "env", Value: "prod"},
+		{Key: "server", Value: "app"},
+		{Key: "message", Value: "hello"},
+		{Key: "env", Value: "dev"},
+	}
+
+	kept, removed, added := diffReplayInputs(inputs, given, nil)
+
+	wantKept := []struct{ Key, Value string }{
+		{Key: "env", Value: "prod"},
+		{Key: "message", Value: "hello"},
+		{Key: "env", Value: "dev"},
+	}
+	if !reflect.DeepEqual(kept, wantKept) {
+		t.Errorf("Expected is %v but got %v\n", wantKept, kept)
+	}
+
+	wantRemoved := []string{"server"}
+	if !reflect.DeepEqual(removed, wantRemoved) {
+		t.Errorf("Expected is %v but got %v\n", wantRemoved, removed)
+	}
+
+	wantAdded := map[string]bool{"region": true}
+	if !reflect.DeepEqual(added, wantAdded) {
+		t.Errorf("Expected is %v but got %v\n", wantAdded, added)
+	}
+
+	// region was left to its default at the dispatch
+	_, _, added = diffReplayInputs(inputs, given, []string{"env", "message", "region", "server"})
+	if len(added) != 0 {
+		t.Errorf("Expected is %v but got %v\n", map[string]bool{}, added)
+	}
+}
+
+func TestNewInputResult(t *testing.T) {
+	src, err := os.ReadFile("../../testdata/valid-all-types.yml")
+	if err != nil {
+		t.Fatalf("Error reading file: %s\n", err)
+	}
+
+	workflow := subproc.GhWorkflow{Id: "1", Name: "TestInput", Path: ".github/workflows/test.yml", Status: "active"}
+	client := &subproc.FakeClient{
+		Workflows: []subproc.GhWorkflow{
+			{Id: "2", Name: "Build", Path: ".github/workflows/build.yml", Status: "active"},
+			workflow,
+		},
+		Files: map[subproc.FakeFileKey][]byte{
+			{Path: workflow.Path, Ref: ""}:     src,
+			{Path: workflow.Path, Ref: "main"}: src,
+		},
+		Environments: []string{"production", "staging"},
+	}
+
+	opts := Options{
+		Ref:      "main",
+		Workflow: "test.yml",
+		Inputs: []struct{ Key, Value string }{
+			{Key: "choice-all", Value: "optionC"},
+			{Key: "boolean-no-default", Value: "false"},
+		},
+		Yes:    true,
+		Client: client,
+	}
+
+	got, err := NewInputResult(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	// inputs with a default are left to GitHub when not given
+	want := &InputResult{
+		Branch:   "main",
+		Workflow: workflow,
+		WorkflowInputs: []struct{ Key, Value string }{
+			{Key: "choice-all", Value: "optionC"},
+			{Key: "boolean-no-default", Value: "false"},
+		},
+		WorkflowInputNames: []string{
+			"string-all", "string-no-type-required", "choice-all",
+			"boolean-default-true", "boolean-default-false", "boolean-no-default", "environment-all",
+		},
+		IsRun:  true,
+		client: client,
+		out:    os.Stdout,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected is %v but got %v\n", want, got)
+	}
+
+	// a required input without default must be given when not interactive
+	opts.Inputs = opts.Inputs[:1]
+	if _, err := NewInputResult(context.Background(), opts); err == nil {
+		t.Errorf("Expected error but got nil\n")
+	}
+
+	// confirmation is required without --yes
+	opts.Inputs = nil
+	opts.Yes = false
+	opts.Workflow = "build.yml"
+	client.Files[subproc.FakeFileKey{Path: ".github/workflows/build.yml", Ref: "main"}] = []byte("on: workflow_dispatch\n")
+	client.Files[subproc.FakeFileKey{Path: ".github/workflows/build.yml", Ref: ""}] = []byte("on: workflow_dispatch\n")
+	if _, err := NewInputResult(context.Background(), opts); err == nil || !strings.Contains(err.Error(), "Confirmation required") {
+		t.Errorf("Expected confirmation error but got %v\n", err)
+	}
+
+	// disabled workflows are only listed with --all and cannot be enabled without a terminal
+	opts.Yes = true
+	opts.Workflow = "lint.yml"
+	client.Workflows = append(client.Workflows, subproc.GhWorkflow{Id: "3", Name: "Lint", Path: ".github/workflows/lint.yml", Status: "disabled_manually"})
+	if _, err := NewInputResult(context.Background(), opts); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error but got %v\n", err)
+	}
+	opts.All = true
+	if _, err := NewInputResult(context.Background(), opts); err == nil || !strings.Contains(err.Error(), "disabled") {
+		t.Errorf("Expected disabled error but got %v\n", err)
+	}
+
+	// failures after a cancellation are reported as such
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewInputResult(ctx, opts); !errors.Is(err, ErrCanceled) {
+		t.Errorf("Expected is %v but got %v\n", ErrCanceled, err)
+	}
+}
+
+func TestLoadWorkflowInputs(t *testing.T) {
+	workflow := subproc.GhWorkflow{Id: "1", Name: "Deploy", Path: ".github/workflows/deploy.yml"}
+	client := &subproc.FakeClient{
+		Files: map[subproc.FakeFileKey][]byte{
+			{Path: workflow.Path, Ref: ""}:       []byte("on:\n  workflow_dispatch:\n    inputs:\n      env:\n        required: true\n"),
+			{Path: workflow.Path, Ref: "broken"}: []byte("on:\n  workflow_dispatch:\n    inputs:\n      env:\n        required: maybe\n"),
+		},
+	}
+
+	// a workflow missing on the branch falls back to the default branch
+	r := &InputResult{client: client, Workflow: workflow, Branch: "feature"}
+	got, err := r.loadWorkflowInputs(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if len(got) != 1 || got[0].Name != "env" {
+		t.Errorf("Expected is %v but got %v\n", "[env]", got)
+	}
+
+	// an invalid file on the branch is reported
+	r.Branch = "broken"
+	var parseErr *subproc.WorkflowParseError
+	if _, err := r.loadWorkflowInputs(context.Background()); !errors.As(err, &parseErr) {
+		t.Errorf("Expected *WorkflowParseError but got %v\n", err)
+	}
+}
+
+func TestWorkflowLabel(t *testing.T) {
+	testCases := []struct {
+		name     string
+		workflow subproc.GhWorkflow
+		want     string
+	}{
+		{name: "active", workflow: subproc.GhWorkflow{Name: "Build", Status: "active"}, want: "Build"},
+		{name: "disabled manually", workflow: subproc.GhWorkflow{Name: "Build", Status: "disabled_manually"}, want: "Build (disabled manually)"},
+		{name: "disabled inactivity", workflow: subproc.GhWorkflow{Name: "Build", Status: "disabled_inactivity"}, want: "Build (disabled inactivity)"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := workflowLabel(test.workflow)
+			if got != test.want {
+				t.Errorf("Expected is %s but got %s\n", test.want, got)
+			}
+		})
+	}
+}
+
+func TestOrderRefs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		currentBranch string
+		branches      []string
+		tags          []string
+		want          []string
+	}{
+		{
+			name:          "branches only",
+			currentBranch: "feature",
+			branches:      []string{"main", "feature"},
+			tags:          []string{},
+			want:          []string{"feature", "main"},
+		},
+		{
+			name:          "current branch not pushed",
+			currentBranch: "local",
+			branches:      []string{"main"},
+			tags:          []string{"v1.0.0"},
+			want:          []string{"v1.0.0", "main"},
+		},
+		{
+			name:          "recent tags first",
+			currentBranch: "main",
+			branches:      []string{"main", "feature/login"},
+			tags:          []string{"v1.4.2", "v1.4.1", "v1.4.0", "v1.3.0"},
+			want:          []string{"main", "v1.4.2", "v1.4.1", "v1.4.0", "feature/login", "v1.3.0"},
+		},
+		{
+			name:          "tag named like a branch",
+			currentBranch: "main",
+			branches:      []string{"main", "release"},
+			tags:          []string{"release"},
+			want:          []string{"main", "release"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := orderRefs(test.currentBranch, test.branches, test.tags)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Expected is %v but got %v\n", test.want, got)
+			}
+		})
+	}
+}
+
+func TestDefaultChoice(t *testing.T) {
+	choices := []string{"development", "production", "staging"}
+
+	testCases := []struct {
+		name         string
+		defaultValue string
+		presetInputs map[string]string
+		want         string
+	}{
+		{name: "no default", want: "development"},
+		{name: "declared default", defaultValue: "staging", want: "staging"},
+		{name: "default not among choices", defaultValue: "qa", want: "development"},
+		{name: "preset value", defaultValue: "staging", presetInputs: map[string]string{"env": "production"}, want: "production"},
+		{name: "preset value not among choices", defaultValue: "staging", presetInputs: map[string]string{"env": "qa"}, want: "staging"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			r := InputResult{presetInputs: test.presetInputs}
+			v := subproc.GhWorkflowInput{Name: "env", Default: test.defaultValue}
+
+			got := r.defaultChoice(v, choices)
+
+			if got != test.want {
+				t.Errorf("Expected is %s but got %s\n", test.want, got)
+			}
+		})
+	}
 }
@@ -0,0 +1,72 @@
+package input
+
+import (
+	"context"
+
+	"github.com/t4kamura/gh-wrun/internal/config"
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+// lastSelectionFile stores the last answers of the pickers.
+const lastSelectionFile = "last-selection.json"
+
+// Picker kinds whose last selection is remembered.
+const (
+	lastSelectionRef      = "ref"
+	lastSelectionWorkflow = "workflow"
+)
+
+// lastSelections holds the last selection of every picker kind, keyed by repository.
+type lastSelections map[string]map[string]string
+
+// loadLastSelection returns the last selection of the picker kind for repo,
+// or an empty string if there is none.
+func loadLastSelection(ctx context.Context, repo subproc.Repo, kind string) string {
+	key, err := subproc.GetRepositoryWithOwner(ctx, repo)
+	if err != nil {
+		return ""
+	}
+
+	path, err := config.Path(lastSelectionFile)
+	if err != nil {
+		return ""
+	}
+
+	s, err := config.OpenStore[lastSelections](path)
+	if err != nil {
+		warnf("Failed to read the last selection: %s", err)
+		return ""
+	}
+	return s.Data[key][kind]
+}
+
+// saveLastSelection remembers value as the last selection of the picker kind for repo.
+func saveLastSelection(ctx context.Context, repo subproc.Repo, kind, value string) {
+	key, err := subproc.GetRepositoryWithOwner(ctx, repo)
+	if err != nil {
+		return
+	}
+
+	path, err := config.Path(lastSelectionFile)
+	if err != nil {
+		return
+	}
+
+	s, err := config.OpenStore[lastSelections](path)
+	if err != nil {
+		warnf("Failed to read the last selection: %s", err)
+		return
+	}
+
+	if s.Data == nil {
+		s.Data = lastSelections{}
+	}
+	if s.Data[key] == nil {
+		s.Data[key] = map[string]string{}
+	}
+	s.Data[key][kind] = value
+
+	if err := s.Save(); err != nil {
+		warnf("Failed to save the last selection: %s", err)
+	}
+}
@@ -0,0 +1,69 @@
+package input
+
+import (
+	"context"
+	"sync"
+
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+// prefetcher loads in the background what the prompts will need,
+// so that they appear without waiting. The client must remember
+// what it got, see subproc.CachingClient.
+// Work not started yet is dropped once ctx is done.
+type prefetcher struct {
+	ctx    context.Context
+	client subproc.Client
+
+	mu      sync.Mutex
+	started map[string]bool
+	wg      sync.WaitGroup
+}
+
+func newPrefetcher(ctx context.Context, client subproc.Client) *prefetcher {
+	// the user is not waiting for background loads, they show no progress
+	if c, ok := client.(interface{ Unwrap() subproc.Client }); ok {
+		client = c.Unwrap()
+	}
+	return &prefetcher{ctx: ctx, client: client, started: map[string]bool{}}
+}
+
+// start runs f in the background once per key, unless ctx is done.
+func (p *prefetcher) start(key string, f func()) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.started[key] {
+		return
+	}
+	p.started[key] = true
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		if p.ctx.Err() != nil {
+			return
+		}
+		f()
+	}()
+}
+
+// wait waits for the started work to return.
+func (p *prefetcher) wait() {
+	p.wg.Wait()
+}
+
+// workflows starts loading the workflows and the environments.
+func (p *prefetcher) workflows(all bool) {
+	p.start("workflows", func() { _, _ = p.client.ListWorkflows(p.ctx, all) })
+	p.start("environments", func() { _, _ = p.client.ListEnvironments(p.ctx) })
+}
+
+// workflowFile starts loading the workflow file on ref,
+// e.g. for the workflow highlighted in the picker.
+func (p *prefetcher) workflowFile(w subproc.GhWorkflow, ref string) {
+	p.start("file:"+string(w.Id)+":"+ref, func() { _, _ = p.client.GetWorkflowFile(p.ctx, w, ref) })
+}
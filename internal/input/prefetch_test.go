@@ -0,0 +1,66 @@
+package input
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+// recordingClient records the workflow files got from FakeClient.
+type recordingClient struct {
+	subproc.FakeClient
+	mu    sync.Mutex
+	files []string
+}
+
+func (c *recordingClient) GetWorkflowFile(ctx context.Context, w subproc.GhWorkflow, ref string) ([]byte, error) {
+	c.mu.Lock()
+	c.files = append(c.files, w.Path+"@"+ref)
+	c.mu.Unlock()
+	return c.FakeClient.GetWorkflowFile(ctx, w, ref)
+}
+
+func (c *recordingClient) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.files)
+}
+
+func TestPrefetcher(t *testing.T) {
+	w := subproc.GhWorkflow{Id: "1", Name: "Deploy", Path: ".github/workflows/deploy.yml"}
+
+	t.Run("started once", func(t *testing.T) {
+		client := &recordingClient{}
+		p := newPrefetcher(context.Background(), client)
+
+		for i := 0; i < 3; i++ {
+			p.workflowFile(w, "")
+		}
+		p.wait()
+
+		if client.count() != 1 {
+			t.Errorf("Expected is %d but got %d\n", 1, client.count())
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		client := &recordingClient{}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		p := newPrefetcher(ctx, client)
+		p.workflowFile(w, "")
+		p.wait()
+
+		if client.count() != 0 {
+			t.Errorf("Expected is %d but got %d\n", 0, client.count())
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var p *prefetcher
+		p.workflowFile(w, "")
+	})
+}
@@ -0,0 +1,99 @@
+package input
+
+import (
+	"context"
+
+	"github.com/t4kamura/gh-wrun/internal/interactive"
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+const (
+	preflightPush   = "Push and run"
+	preflightIgnore = "Run without pushing"
+	preflightAbort  = "Abort"
+)
+
+// currentLocalRef returns the branch checked out in the local repository.
+// A detached HEAD resolves to the tag pointing at it, or an empty string.
+func currentLocalRef(ctx context.Context) (string, error) {
+	branch, err := subproc.GetBranchName(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if branch != "HEAD" {
+		return branch, nil
+	}
+
+	return subproc.GetExactTag(ctx)
+}
+
+// checkBranchPushed checks that the selected branch, when it is the local one,
+// is pushed and up to date, since the workflow runs on the remote commits.
+// It warns about unpushed commits, uncommitted changes or a missing upstream,
+// and offers to push or abort when interactive and not a dry run.
+func (r *InputResult) checkBranchPushed(ctx context.Context, opts Options) error {
+	if !opts.Repo.IsLocal() {
+		return nil
+	}
+
+	branch, err := subproc.GetBranchName(ctx)
+	if err != nil || branch != r.Branch {
+		return nil
+	}
+
+	status, err := subproc.GetBranchStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	needPush := false
+	pushRemote := ""
+	if status.Upstream == "" {
+		warnf("Branch %s has no upstream, it may not exist on GitHub", branch)
+		needPush = true
+		pushRemote = opts.Remote
+		if pushRemote == "" {
+			remotes, err := subproc.GetRemotes(ctx)
+			if err != nil {
+				return err
+			}
+			pushRemote = defaultRemote(ctx, opts, remotes)
+		}
+	} else if status.Ahead > 0 {
+		warnf("Branch %s has %d unpushed commit(s), the workflow runs on %s", branch, status.Ahead, status.Upstream)
+		needPush = true
+	}
+
+	if status.Behind > 0 {
+		warnf("Branch %s is %d commit(s) behind %s, the workflow runs on the remote commits", branch, status.Behind, status.Upstream)
+	}
+
+	if status.Dirty {
+		warnf("Uncommitted changes are not included in the run")
+	}
+
+	if !opts.Interactive || opts.DryRun {
+		return nil
+	}
+
+	if needPush {
+		answer, err := interactive.AskChoices("Push the branch first?", []string{preflightPush, preflightIgnore, preflightAbort}, preflightPush)
+		if err != nil {
+			return err
+		}
+
+		switch answer {
+		case preflightPush:
+			return subproc.Push(ctx, pushRemote, branch)
+		case preflightAbort:
+			return ErrCanceled
+		}
+	} else if status.Dirty {
+		if !interactive.AskConfirm("Run anyway") {
+			return ErrCanceled
+		}
+	}
+
+	return nil
+}
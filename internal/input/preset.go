@@ -0,0 +1,112 @@
+package input
+
+import (
+	"context"
+
+	"github.com/t4kamura/gh-wrun/internal/interactive"
+	"github.com/t4kamura/gh-wrun/internal/preset"
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+// applyPreset loads the preset named in opts and returns opts pre-filled with it.
+// The branch and workflow of the preset are used unless given explicitly,
+// and its inputs become the default answers.
+func (r *InputResult) applyPreset(ctx context.Context, opts Options) (Options, error) {
+	repo, err := subproc.GetRepositoryWithOwner(ctx, opts.Repo)
+	if err != nil {
+		return opts, err
+	}
+
+	s, err := preset.Open()
+	if err != nil {
+		return opts, err
+	}
+
+	// presets are saved with the path, --workflow may be a name or an ID
+	workflow := opts.Workflow
+	if workflow != "" {
+		workflows, err := r.client.ListWorkflows(ctx, opts.All)
+		if err != nil {
+			return opts, err
+		}
+		if w, err := subproc.FindWorkflow(workflows, workflow); err == nil {
+			workflow = w.Path
+		}
+	}
+
+	p, err := s.Find(repo, opts.Preset, workflow)
+	if err != nil {
+		return opts, err
+	}
+
+	if opts.Ref == "" && opts.BranchAuto {
+		opts.Ref = p.Branch
+	}
+	if opts.Workflow == "" {
+		opts.Workflow = p.Workflow
+	}
+
+	r.presetInputs = map[string]string{}
+	for _, i := range p.Inputs {
+		r.presetInputs[i.Key] = i.Value
+	}
+
+	return opts, nil
+}
+
+// dropUnknownPresetInputs removes preset inputs that the workflow no longer has.
+func (r *InputResult) dropUnknownPresetInputs(inputs []subproc.GhWorkflowInput) {
+	for k := range r.presetInputs {
+		found := false
+		for _, v := range inputs {
+			if v.Name == k {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			warnf("Preset input %q is not defined in the workflow, ignored", k)
+			delete(r.presetInputs, k)
+		}
+	}
+}
+
+// askSavePreset offers to save the answers as a preset.
+// Failing to save is reported as a warning since the workflow is run anyway.
+func (r *InputResult) askSavePreset(ctx context.Context, name string) {
+	if !interactive.AskConfirmWithDefault("Save these answers as a preset", false) {
+		return
+	}
+
+	name, err := interactive.AskInput("Preset name", name, nil)
+	if err != nil || name == "" {
+		return
+	}
+
+	if err := r.savePreset(ctx, name); err != nil {
+		warnf("Failed to save preset %q: %s", name, err)
+	}
+}
+
+// savePreset saves the answers as the preset named name.
+func (r *InputResult) savePreset(ctx context.Context, name string) error {
+	repo, err := subproc.GetRepositoryWithOwner(ctx, r.Workflow.Repo)
+	if err != nil {
+		return err
+	}
+
+	s, err := preset.Open()
+	if err != nil {
+		return err
+	}
+
+	s.Put(repo, preset.Preset{
+		Name:     name,
+		Workflow: r.Workflow.Path,
+		Branch:   r.Branch,
+		Inputs:   r.WorkflowInputs,
+	})
+
+	return s.Save()
+}
@@ -0,0 +1,74 @@
+package input
+
+import (
+	"context"
+	"testing"
+
+	"github.com/t4kamura/gh-wrun/internal/preset"
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+func TestApplyPreset(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	s, err := preset.Open()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	for _, p := range []preset.Preset{
+		{Name: "prod", Workflow: ".github/workflows/deploy.yml", Branch: "main", Inputs: []struct{ Key, Value string }{{"env", "production"}}},
+		{Name: "prod", Workflow: ".github/workflows/build.yml", Branch: "release"},
+	} {
+		s.Put("t4kamura/gh-wrun", p)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	client := &subproc.FakeClient{
+		Workflows: []subproc.GhWorkflow{
+			{Id: "1", Name: "Deploy", Path: ".github/workflows/deploy.yml", Status: "active"},
+			{Id: "2", Name: "Build", Path: ".github/workflows/build.yml", Status: "active"},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		workflow  string
+		want      string
+		expectErr bool
+	}{
+		{name: "path", workflow: ".github/workflows/deploy.yml", want: "main"},
+		{name: "file name", workflow: "deploy.yml", want: "main"},
+		{name: "name", workflow: "Deploy", want: "main"},
+		{name: "ID", workflow: "2", want: "release"},
+		{name: "ambiguous", workflow: "", expectErr: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			r := &InputResult{client: client}
+			opts := Options{
+				BranchAuto: true,
+				Workflow:   test.workflow,
+				Preset:     "prod",
+				Repo:       subproc.Repo{Owner: "t4kamura", Name: "gh-wrun"},
+			}
+
+			got, err := r.applyPreset(context.Background(), opts)
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("Expected error but got nil\n")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err)
+			}
+			if got.Ref != test.want {
+				t.Errorf("Expected is %s but got %s\n", test.want, got.Ref)
+			}
+		})
+	}
+}
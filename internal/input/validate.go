@@ -0,0 +1,118 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+// matchGivenInputs checks the given inputs against the workflow inputs
+// and returns them as a map keyed by input name.
+func matchGivenInputs(inputs []subproc.GhWorkflowInput, given []struct{ Key, Value string }) (map[string]string, error) {
+	m := map[string]string{}
+
+	for _, g := range given {
+		var found *subproc.GhWorkflowInput
+		for i := range inputs {
+			if inputs[i].Name == g.Key {
+				found = &inputs[i]
+				break
+			}
+		}
+
+		if found == nil {
+			return nil, fmt.Errorf("Unknown input %q", g.Key)
+		}
+
+		if err := validateInputValue(*found, g.Value); err != nil {
+			return nil, err
+		}
+
+		m[g.Key] = g.Value
+	}
+
+	return m, nil
+}
+
+// validateAnswers checks the answers against the workflow inputs before dispatch.
+// Inputs left unanswered are filled with their default by GitHub,
+// so only required inputs without default must be answered.
+func validateAnswers(inputs []subproc.GhWorkflowInput, answers []struct{ Key, Value string }) error {
+	if _, err := matchGivenInputs(inputs, answers); err != nil {
+		return err
+	}
+
+	for _, v := range inputs {
+		if !v.Required || v.Default != "" {
+			continue
+		}
+
+		answered := false
+		for _, a := range answers {
+			if a.Key == v.Name {
+				answered = true
+				break
+			}
+		}
+		if !answered {
+			return fmt.Errorf("Input %q is required", v.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateInputValue checks that value is acceptable for the workflow input.
+func validateInputValue(input subproc.GhWorkflowInput, value string) error {
+	if err := inputValidator(input)(value); err != nil {
+		return fmt.Errorf("Input %q %s, got %q", input.Name, err, value)
+	}
+	return nil
+}
+
+// inputValidator returns a function checking a value for the workflow input.
+// Its errors are short enough to be shown inline in a prompt.
+func inputValidator(input subproc.GhWorkflowInput) func(string) error {
+	return func(value string) error {
+		if input.Required && value == "" {
+			return errors.New("is required")
+		}
+
+		switch input.Type {
+		case subproc.GhWorkflowInputTypeChoice:
+			for _, o := range input.Options {
+				if o == value {
+					return nil
+				}
+			}
+			return fmt.Errorf("must be one of %v", input.Options)
+		case subproc.GhWorkflowInputTypeBoolean:
+			if value != "true" && value != "false" {
+				return errors.New("must be true or false")
+			}
+		case subproc.GhWorkflowInputTypeNumber:
+			if value == "" {
+				return nil
+			}
+			if !isNumber(value) {
+				return errors.New("must be a number")
+			}
+		}
+
+		return nil
+	}
+}
+
+// isNumber reports whether value is a finite decimal number, e.g. "-1.5" or "1e3".
+// ParseFloat also accepts "NaN", "Inf" and hex forms like "0x1p4".
+func isNumber(value string) bool {
+	if strings.ContainsAny(value, "xX") {
+		return false
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	return err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+}
@@ -0,0 +1,166 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/t4kamura/gh-wrun/internal/subproc"
+)
+
+func TestMatchGivenInputs(t *testing.T) {
+	inputs := []subproc.GhWorkflowInput{
+		{Name: "env", Type: subproc.GhWorkflowInputTypeChoice, Options: []string{"dev", "prod"}, Required: true},
+		{Name: "dry_run", Type: subproc.GhWorkflowInputTypeBoolean},
+		{Name: "message", Type: subproc.GhWorkflowInputTypeString, Required: true},
+		{Name: "replicas", Type: subproc.GhWorkflowInputTypeNumber},
+	}
+
+	testCases := []struct {
+		name      string
+		given     []struct{ Key, Value string }
+		want      map[string]string
+		expectErr bool
+	}{
+		{
+			name:  "valid",
+			given: []struct{ Key, Value string }{{"env", "prod"}, {"dry_run", "true"}},
+			want:  map[string]string{"env": "prod", "dry_run": "true"},
+		},
+		{
+			name:  "none",
+			given: nil,
+			want:  map[string]string{},
+		},
+		{
+			name:      "unknown input",
+			given:     []struct{ Key, Value string }{{"server", "app"}},
+			expectErr: true,
+		},
+		{
+			name:      "choice not in options",
+			given:     []struct{ Key, Value string }{{"env", "stg"}},
+			expectErr: true,
+		},
+		{
+			name:      "invalid boolean",
+			given:     []struct{ Key, Value string }{{"dry_run", "yes"}},
+			expectErr: true,
+		},
+		{
+			name:  "number",
+			given: []struct{ Key, Value string }{{"replicas", "-1.5"}},
+			want:  map[string]string{"replicas": "-1.5"},
+		},
+		{
+			name:  "exponent",
+			given: []struct{ Key, Value string }{{"replicas", "1e3"}},
+			want:  map[string]string{"replicas": "1e3"},
+		},
+		{
+			name:      "invalid number",
+			given:     []struct{ Key, Value string }{{"replicas", "three"}},
+			expectErr: true,
+		},
+		{
+			name:      "NaN",
+			given:     []struct{ Key, Value string }{{"replicas", "NaN"}},
+			expectErr: true,
+		},
+		{
+			name:      "infinity",
+			given:     []struct{ Key, Value string }{{"replicas", "-Infinity"}},
+			expectErr: true,
+		},
+		{
+			name:      "inf",
+			given:     []struct{ Key, Value string }{{"replicas", "Inf"}},
+			expectErr: true,
+		},
+		{
+			name:      "hex float",
+			given:     []struct{ Key, Value string }{{"replicas", "0x1p4"}},
+			expectErr: true,
+		},
+		{
+			name:      "hex",
+			given:     []struct{ Key, Value string }{{"replicas", "0X10"}},
+			expectErr: true,
+		},
+		{
+			name:      "empty required",
+			given:     []struct{ Key, Value string }{{"message", ""}},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := matchGivenInputs(inputs, test.given)
+
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("Expected error but got nil\n")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Expected is %v but got %v\n", test.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateAnswers(t *testing.T) {
+	inputs := []subproc.GhWorkflowInput{
+		{Name: "env", Type: subproc.GhWorkflowInputTypeChoice, Options: []string{"dev", "prod"}, Default: "dev", Required: true},
+		{Name: "message", Type: subproc.GhWorkflowInputTypeString, Required: true},
+		{Name: "note", Type: subproc.GhWorkflowInputTypeString},
+	}
+
+	testCases := []struct {
+		name      string
+		answers   []struct{ Key, Value string }
+		expectErr bool
+	}{
+		{
+			name:    "all answered",
+			answers: []struct{ Key, Value string }{{"env", "prod"}, {"message", "hello"}, {"note", ""}},
+		},
+		{
+			name:    "default left to GitHub",
+			answers: []struct{ Key, Value string }{{"message", "hello"}},
+		},
+		{
+			name:      "required without default missing",
+			answers:   []struct{ Key, Value string }{{"env", "prod"}},
+			expectErr: true,
+		},
+		{
+			name:      "required answered empty",
+			answers:   []struct{ Key, Value string }{{"message", ""}},
+			expectErr: true,
+		},
+		{
+			name:      "invalid choice",
+			answers:   []struct{ Key, Value string }{{"env", "stg"}, {"message", "hello"}},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateAnswers(inputs, test.answers)
+
+			if test.expectErr && err == nil {
+				t.Errorf("Expected error but got nil\n")
+			} else if !test.expectErr && err != nil {
+				t.Errorf("Unexpected error: %s\n", err)
+			}
+		})
+	}
+}
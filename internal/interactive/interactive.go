@@ -1,12 +1,38 @@
 package interactive
 
 import (
+	"errors"
+	"io"
+	"os"
 	"strconv"
 
 	"github.com/manifoldco/promptui"
+	"github.com/t4kamura/gh-wrun/internal/progress"
 )
 
+// ErrInterrupted is returned when the user interrupts a prompt with Ctrl-C or Ctrl-D.
+var ErrInterrupted = errors.New("Interrupted")
+
+// stdout is where the prompts are written.
+var stdout io.WriteCloser = os.Stdout
+
+// SetOutput writes the prompts to w instead of stdout,
+// e.g. to keep stdout for a machine readable output.
+func SetOutput(w io.WriteCloser) {
+	stdout = w
+}
+
+// promptError returns ErrInterrupted for interrupted prompts, err otherwise.
+func promptError(err error) error {
+	if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+		return ErrInterrupted
+	}
+	return err
+}
+
 func AskChoices(message string, choices []string, defaultInput string) (string, error) {
+	defer progress.Suspend()()
+
 	defaultCursor := 0
 	for i, choice := range choices {
 		if choice == defaultInput {
@@ -19,31 +45,41 @@ func AskChoices(message string, choices []string, defaultInput string) (string,
 		CursorPos: defaultCursor,
 		HideHelp:  true,
 		Size:      10,
+		Stdout:    stdout,
 	}
 	_, result, err := prompt.Run()
 
 	if err != nil {
-		return "", err
+		return "", promptError(err)
 	}
 
 	return result, nil
 }
 
-func AskInput(message string, defaultInput string) (string, error) {
+// AskInput asks for a free text answer.
+// If validate is not nil, the answer is re-asked until it returns nil,
+// and its error is shown inline while typing.
+func AskInput(message string, defaultInput string, validate func(string) error) (string, error) {
+	defer progress.Suspend()()
+
 	prompt := promptui.Prompt{
-		Label:   message,
-		Default: defaultInput,
+		Label:    message,
+		Default:  defaultInput,
+		Validate: validate,
+		Stdout:   stdout,
 	}
 
 	result, err := prompt.Run()
 	if err != nil {
-		return "", err
+		return "", promptError(err)
 	}
 
 	return result, nil
 }
 
 func AskBool(message string, defaultInput bool) (bool, error) {
+	defer progress.Suspend()()
+
 	choices := []bool{true, false}
 	defaultCursor := 0
 	for i, choice := range choices {
@@ -56,11 +92,12 @@ func AskBool(message string, defaultInput bool) (bool, error) {
 		Items:     choices,
 		CursorPos: defaultCursor,
 		HideHelp:  true,
+		Stdout:    stdout,
 	}
 	_, result, err := prompt.Run()
 
 	if err != nil {
-		return false, err
+		return false, promptError(err)
 	}
 	resultBool, _ := strconv.ParseBool(result)
 
@@ -68,17 +105,44 @@ func AskBool(message string, defaultInput bool) (bool, error) {
 }
 
 func AskConfirm(message string) bool {
+	return AskConfirmWithDefault(message, true)
+}
+
+// AskConfirmWithDefault asks a yes/no question.
+// An empty answer is treated as defaultYes.
+func AskConfirmWithDefault(message string, defaultYes bool) bool {
+	defer progress.Suspend()()
+
 	prompt := promptui.Prompt{
 		Label:     message,
 		IsConfirm: true,
-		Default:   "y",
+		Stdout:    stdout,
+	}
+	if defaultYes {
+		prompt.Default = "y"
 	}
 
 	result, err := prompt.Run()
 
+	if err != nil {
+		// promptui returns an error for "n" and for an empty answer without default
+		return false
+	}
+
+	return result == "y" || result == "Y" || (result == "" && defaultYes)
+}
+
+// Highlight returns s styled to stand out in prompts.
+func Highlight(s string) string {
+	return promptui.Styler(promptui.FGYellow, promptui.FGBold)(s)
+}
+
+// IsTerminal reports whether stdin is attached to a terminal.
+func IsTerminal() bool {
+	fi, err := os.Stdin.Stat()
 	if err != nil {
 		return false
 	}
 
-	return result == "y" || result == "Y" || result == ""
+	return fi.Mode()&os.ModeCharDevice != 0
 }
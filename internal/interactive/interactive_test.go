@@ -0,0 +1,31 @@
+package interactive
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/manifoldco/promptui"
+)
+
+func TestPromptError(t *testing.T) {
+	other := errors.New("other")
+
+	testCases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "ctrl-c", err: promptui.ErrInterrupt, want: ErrInterrupted},
+		{name: "ctrl-d", err: promptui.ErrEOF, want: ErrInterrupted},
+		{name: "other", err: other, want: other},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := promptError(test.err)
+			if !errors.Is(got, test.want) {
+				t.Errorf("Expected is %v but got %v\n", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package interactive
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/manifoldco/promptui"
+	"github.com/t4kamura/gh-wrun/internal/progress"
+)
+
+// Choice is an item of a searchable picker.
+type Choice struct {
+	Label string
+	// Keywords are searched in addition to the label, e.g. a file path.
+	Keywords []string
+	// Unavailable is the reason the choice cannot be used.
+	// Such choices are greyed out with the reason.
+	Unavailable string
+	// OnHighlight is called whenever the cursor is on the choice,
+	// e.g. to load what selecting it needs. It must not block.
+	OnHighlight func()
+}
+
+// pageSize is the number of choices shown at once.
+const pageSize = 10
+
+// AskSearch asks the user to select one of choices with incremental fuzzy search.
+// The matched characters of the label are highlighted.
+// The cursor starts on the choice at defaultIndex, and the index
+// of the selected choice is returned, since labels may not be unique.
+func AskSearch(message string, choices []Choice, defaultIndex int) (int, error) {
+	defer progress.Suspend()()
+
+	defaultCursor := 0
+	if defaultIndex >= 0 && defaultIndex < len(choices) {
+		defaultCursor = defaultIndex
+	}
+
+	// the term is kept for the highlight of the rendered labels
+	term := ""
+
+	funcMap := template.FuncMap{}
+	for k, v := range promptui.FuncMap {
+		funcMap[k] = v
+	}
+	funcMap["highlight"] = func(s string) string {
+		return highlightMatches(s, term)
+	}
+	funcMap["notify"] = func(c Choice) string {
+		if c.OnHighlight != nil {
+			c.OnHighlight()
+		}
+		return ""
+	}
+
+	prompt := promptui.Select{
+		Label:     message,
+		Items:     choices,
+		CursorPos: defaultCursor,
+		Size:      pageSize,
+		Templates: &promptui.SelectTemplates{
+			Label:    fmt.Sprintf("%s {{ . }}: ", promptui.IconInitial),
+			Active:   fmt.Sprintf("{{ notify . }}%s {{ if .Unavailable }}{{ .Label | faint | underline }} {{ printf \"(%%s)\" .Unavailable | faint }}{{ else }}{{ .Label | highlight | underline }}{{ end }}", promptui.IconSelect),
+			Inactive: `  {{ if .Unavailable }}{{ .Label | faint }} {{ printf "(%s)" .Unavailable | faint }}{{ else }}{{ .Label | highlight }}{{ end }}`,
+			Selected: fmt.Sprintf(`{{ "%s" | green }} {{ .Label | faint }}`, promptui.IconGood),
+			FuncMap:  funcMap,
+		},
+		Searcher: func(input string, index int) bool {
+			term = input
+			c := choices[index]
+			for _, s := range append([]string{c.Label}, c.Keywords...) {
+				if _, ok := fuzzyMatch(s, input); ok {
+					return true
+				}
+			}
+			return false
+		},
+		// long lists are filtered right away, "/" toggles the search otherwise
+		StartInSearchMode: len(choices) > pageSize,
+		Stdout:            stdout,
+	}
+
+	i, _, err := prompt.Run()
+	if err != nil {
+		return 0, promptError(err)
+	}
+
+	return i, nil
+}
+
+// fuzzyMatch reports whether the characters of term appear in s in order,
+// ignoring case and spaces, and returns the rune positions of the matches in s.
+func fuzzyMatch(s, term string) ([]int, bool) {
+	t := []rune(strings.ToLower(strings.ReplaceAll(term, " ", "")))
+	if len(t) == 0 {
+		return nil, true
+	}
+
+	positions := []int{}
+	j := 0
+	for i, r := range []rune(s) {
+		if unicode.ToLower(r) == t[j] {
+			positions = append(positions, i)
+			j++
+			if j == len(t) {
+				return positions, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// highlightMatches styles the characters of s matched by term.
+func highlightMatches(s, term string) string {
+	positions, ok := fuzzyMatch(s, term)
+	if !ok || len(positions) == 0 {
+		return s
+	}
+
+	style := promptui.Styler(promptui.FGCyan, promptui.FGBold)
+
+	var b strings.Builder
+	p := 0
+	for i, r := range []rune(s) {
+		if p < len(positions) && positions[p] == i {
+			b.WriteString(style(string(r)))
+			p++
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package interactive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	testCases := []struct {
+		name          string
+		s             string
+		term          string
+		wantPositions []int
+		wantOk        bool
+	}{
+		{name: "empty term", s: "deploy.yml", term: "", wantPositions: nil, wantOk: true},
+		{name: "prefix", s: "deploy.yml", term: "dep", wantPositions: []int{0, 1, 2}, wantOk: true},
+		{name: "subsequence", s: "Deploy Production", term: "dprod", wantPositions: []int{0, 2, 8, 9, 10}, wantOk: true},
+		{name: "ignore case and spaces", s: "Deploy Production", term: "DE P", wantPositions: []int{0, 1, 2}, wantOk: true},
+		{name: "slashes", s: "feature/login", term: "f/l", wantPositions: []int{0, 7, 8}, wantOk: true},
+		{name: "out of order", s: "deploy.yml", term: "yd", wantOk: false},
+		{name: "no match", s: "deploy.yml", term: "build", wantOk: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			positions, ok := fuzzyMatch(test.s, test.term)
+
+			if ok != test.wantOk {
+				t.Fatalf("Expected ok is %v but got %v\n", test.wantOk, ok)
+			}
+
+			if !reflect.DeepEqual(positions, test.wantPositions) {
+				t.Errorf("Expected is %v but got %v\n", test.wantPositions, positions)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package preset
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/t4kamura/gh-wrun/internal/config"
+)
+
+const fileName = "presets.json"
+
+// Preset is a named set of answers for a workflow.
+type Preset struct {
+	Name     string                        `json:"name"`
+	Workflow string                        `json:"workflow"`
+	Branch   string                        `json:"branch"`
+	Inputs   []struct{ Key, Value string } `json:"inputs"`
+}
+
+// file is the content of the presets file.
+type file struct {
+	Repos map[string][]Preset `json:"repos"`
+}
+
+// Store holds the presets of every repository.
+type Store struct {
+	*config.Store[file]
+}
+
+// Open loads the presets stored in the gh-wrun config directory.
+func Open() (*Store, error) {
+	p, err := config.Path(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return OpenFile(p)
+}
+
+// OpenFile loads the presets stored in the file at path.
+func OpenFile(path string) (*Store, error) {
+	s, err := config.OpenStore[file](path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading presets: %w", err)
+	}
+
+	if s.Data.Repos == nil {
+		s.Data.Repos = map[string][]Preset{}
+	}
+	return &Store{s}, nil
+}
+
+// Find returns the preset named name in repo.
+// If workflow is not empty, only presets whose workflow path or file name
+// matches it are considered.
+func (s *Store) Find(repo, name, workflow string) (Preset, error) {
+	var found []Preset
+	for _, p := range s.Data.Repos[repo] {
+		if p.Name == name && (workflow == "" || p.Workflow == workflow || filepath.Base(p.Workflow) == workflow) {
+			found = append(found, p)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return Preset{}, fmt.Errorf("Preset %q not found", name)
+	case 1:
+		return found[0], nil
+	default:
+		return Preset{}, fmt.Errorf("Preset %q exists for several workflows, use --workflow to choose one", name)
+	}
+}
+
+// Put adds p to repo, replacing the preset with the same name and workflow.
+func (s *Store) Put(repo string, p Preset) {
+	presets := s.Data.Repos[repo]
+	for i, e := range presets {
+		if e.Name == p.Name && e.Workflow == p.Workflow {
+			presets[i] = p
+			return
+		}
+	}
+	s.Data.Repos[repo] = append(presets, p)
+}
@@ -0,0 +1,69 @@
+package preset
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "presets.json")
+
+	s, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("Error opening presets: %s\n", err)
+	}
+
+	hotfix := Preset{
+		Name:     "staging-hotfix",
+		Workflow: ".github/workflows/deploy.yml",
+		Branch:   "main",
+		Inputs:   []struct{ Key, Value string }{{Key: "env", Value: "staging"}},
+	}
+	s.Put("t4kamura/gh-wrun", hotfix)
+	s.Put("t4kamura/gh-wrun", Preset{Name: "nightly", Workflow: ".github/workflows/build.yml"})
+	s.Put("t4kamura/gh-wrun", Preset{Name: "nightly", Workflow: ".github/workflows/test.yml"})
+	// the same name and workflow replaces the preset
+	s.Put("t4kamura/gh-wrun", Preset{Name: "nightly", Workflow: ".github/workflows/test.yml", Branch: "develop"})
+
+	if got := len(s.Data.Repos["t4kamura/gh-wrun"]); got != 3 {
+		t.Errorf("Expected is %d presets but got %d\n", 3, got)
+	}
+
+	testCases := []struct {
+		name      string
+		repo      string
+		preset    string
+		workflow  string
+		want      Preset
+		expectErr bool
+	}{
+		{name: "by name", repo: "t4kamura/gh-wrun", preset: "staging-hotfix", want: hotfix},
+		{name: "by name and workflow", repo: "t4kamura/gh-wrun", preset: "staging-hotfix", workflow: ".github/workflows/deploy.yml", want: hotfix},
+		{name: "by name and workflow file name", repo: "t4kamura/gh-wrun", preset: "staging-hotfix", workflow: "deploy.yml", want: hotfix},
+		{name: "ambiguous", repo: "t4kamura/gh-wrun", preset: "nightly", expectErr: true},
+		{name: "other workflow", repo: "t4kamura/gh-wrun", preset: "staging-hotfix", workflow: ".github/workflows/build.yml", expectErr: true},
+		{name: "other repo", repo: "t4kamura/other", preset: "staging-hotfix", expectErr: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := s.Find(test.repo, test.preset, test.workflow)
+
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("Expected error but got nil\n")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Expected is %v but got %v\n", test.want, got)
+			}
+		})
+	}
+}
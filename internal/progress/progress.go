@@ -0,0 +1,179 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// spinnerDelay is how long a task runs before the spinner shows,
+	// so that fast tasks do not flicker.
+	spinnerDelay = 150 * time.Millisecond
+	// lineDelay is how long a task runs before its line is printed without a terminal.
+	lineDelay = 2 * time.Second
+	// frameInterval is the time between two spinner frames.
+	frameInterval = 100 * time.Millisecond
+)
+
+var frames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Task is a running operation shown by the indicator.
+type Task struct {
+	message   string
+	started   time.Time
+	lineShown bool
+	ind       *Indicator
+}
+
+// Indicator shows the running tasks on a writer, as a spinner on a terminal
+// and as a line per slow task otherwise. It is safe for concurrent use.
+type Indicator struct {
+	out io.Writer
+	tty bool
+
+	spinnerDelay, lineDelay time.Duration
+
+	mu        sync.Mutex
+	tasks     []*Task
+	suspended int
+	// drawn is true while a spinner line is on the terminal.
+	drawn   bool
+	frame   int
+	running bool
+}
+
+// NewIndicator returns an Indicator writing to out.
+// If tty is true, a spinner is drawn, otherwise lines are printed.
+func NewIndicator(out io.Writer, tty bool) *Indicator {
+	return &Indicator{out: out, tty: tty, spinnerDelay: spinnerDelay, lineDelay: lineDelay}
+}
+
+// std is the indicator on stderr.
+var std = NewIndicator(os.Stderr, isTerminal(os.Stderr))
+
+// Start shows message on stderr until the returned task is stopped.
+func Start(message string) *Task {
+	return std.Start(message)
+}
+
+// Suspend hides the indicator on stderr until the returned function is called,
+// e.g. while a prompt or a message is shown.
+func Suspend() (resume func()) {
+	return std.Suspend()
+}
+
+// Start shows message until the returned task is stopped.
+// The newest running task is the one shown.
+func (ind *Indicator) Start(message string) *Task {
+	t := &Task{message: message, started: time.Now(), ind: ind}
+
+	ind.mu.Lock()
+	defer ind.mu.Unlock()
+
+	ind.tasks = append(ind.tasks, t)
+	if !ind.running {
+		ind.running = true
+		go ind.loop()
+	}
+	return t
+}
+
+// Stop removes the task, the spinner is cleared right away when no task is left.
+func (t *Task) Stop() {
+	ind := t.ind
+
+	ind.mu.Lock()
+	defer ind.mu.Unlock()
+
+	for i, task := range ind.tasks {
+		if task == t {
+			ind.tasks = append(ind.tasks[:i], ind.tasks[i+1:]...)
+			break
+		}
+	}
+	if len(ind.tasks) == 0 {
+		ind.clear()
+	}
+}
+
+// Suspend hides the indicator until the returned function is called.
+func (ind *Indicator) Suspend() (resume func()) {
+	ind.mu.Lock()
+	defer ind.mu.Unlock()
+
+	ind.suspended++
+	ind.clear()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ind.mu.Lock()
+			defer ind.mu.Unlock()
+			ind.suspended--
+		})
+	}
+}
+
+// loop renders the tasks until none is left.
+func (ind *Indicator) loop() {
+	ticker := time.NewTicker(frameInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ind.mu.Lock()
+		if len(ind.tasks) == 0 {
+			ind.running = false
+			ind.mu.Unlock()
+			return
+		}
+		ind.render(time.Now())
+		ind.mu.Unlock()
+	}
+}
+
+// render draws the newest task at now. It must be called with mu held.
+func (ind *Indicator) render(now time.Time) {
+	if ind.suspended > 0 || len(ind.tasks) == 0 {
+		return
+	}
+
+	if !ind.tty {
+		for _, t := range ind.tasks {
+			if !t.lineShown && now.Sub(t.started) >= ind.lineDelay {
+				fmt.Fprintf(ind.out, "%s...\n", t.message)
+				t.lineShown = true
+			}
+		}
+		return
+	}
+
+	t := ind.tasks[len(ind.tasks)-1]
+	if now.Sub(t.started) < ind.spinnerDelay {
+		return
+	}
+
+	fmt.Fprintf(ind.out, "\r\033[K%s %s", frames[ind.frame%len(frames)], t.message)
+	ind.frame++
+	ind.drawn = true
+}
+
+// clear erases the spinner line. It must be called with mu held.
+func (ind *Indicator) clear() {
+	if ind.drawn {
+		fmt.Fprint(ind.out, "\r\033[K")
+		ind.drawn = false
+	}
+}
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
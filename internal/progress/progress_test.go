@@ -0,0 +1,49 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestIndicator(t *testing.T) {
+	testCases := []struct {
+		name      string
+		tty       bool
+		suspend   bool
+		elapsed   time.Duration
+		want      string
+		wantAfter string
+	}{
+		{name: "fast task on a terminal", tty: true, elapsed: 10 * time.Millisecond, want: "", wantAfter: ""},
+		{name: "slow task on a terminal", tty: true, elapsed: time.Second, want: "\r\033[K⠋ Loading workflows", wantAfter: "\r\033[K⠋ Loading workflows\r\033[K"},
+		{name: "suspended", tty: true, suspend: true, elapsed: time.Second, want: "", wantAfter: ""},
+		{name: "fast task without a terminal", tty: false, elapsed: time.Second, want: "", wantAfter: ""},
+		{name: "slow task without a terminal", tty: false, elapsed: 3 * time.Second, want: "Loading workflows...\n", wantAfter: "Loading workflows...\n"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			ind := NewIndicator(&out, test.tty)
+
+			task := ind.Start("Loading workflows")
+			if test.suspend {
+				defer ind.Suspend()()
+			}
+
+			ind.mu.Lock()
+			ind.render(task.started.Add(test.elapsed))
+			ind.mu.Unlock()
+
+			if out.String() != test.want {
+				t.Errorf("Expected is %q but got %q\n", test.want, out.String())
+			}
+
+			task.Stop()
+			if out.String() != test.wantAfter {
+				t.Errorf("Expected is %q but got %q\n", test.wantAfter, out.String())
+			}
+		})
+	}
+}
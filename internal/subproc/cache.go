@@ -0,0 +1,243 @@
+package subproc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Time to live of the values kept across sessions.
+// Workflow files keyed by the commit of their ref never expire.
+const (
+	workflowsTTL    = 10 * time.Minute
+	workflowFileTTL = 10 * time.Minute
+	environmentsTTL = time.Hour
+)
+
+// Cache keeps values across sessions.
+type Cache interface {
+	// Get decodes the value of key into v if it was stored less than ttl ago,
+	// a zero ttl never expires. It reports whether v was set.
+	Get(key string, ttl time.Duration, v any) bool
+	// Put stores v as the value of key.
+	Put(key string, v any)
+}
+
+// CachingClient is a Client remembering the workflows, workflow files and environments it got,
+// so that they are fetched once per session even when asked concurrently.
+// With a Cache, they are also reused across sessions.
+// It is safe for concurrent use.
+type CachingClient struct {
+	Client
+
+	repo  Repo
+	cache Cache
+
+	workflows    memo[bool, []GhWorkflow]
+	files        memo[workflowFileKey, []byte]
+	environments memo[struct{}, []string]
+	refShas      memo[string, string]
+
+	// refSha returns the commit of ref on remote, see GetRemoteRefSha.
+	refSha func(ctx context.Context, remote, ref string) (string, error)
+
+	// keysMu guards the values the cache keys depend on.
+	keysMu   sync.Mutex
+	keysDone bool
+	repoKey  string
+	remote   string
+}
+
+type workflowFileKey struct {
+	Id, Ref string
+}
+
+// NewCachingClient returns a Client caching the values got from c about repo.
+// If cache is nil, values are only kept for the session.
+func NewCachingClient(c Client, repo Repo, cache Cache) *CachingClient {
+	return &CachingClient{Client: c, repo: repo, cache: cache, refSha: GetRemoteRefSha}
+}
+
+// resolveCacheKeys returns the repository part of the cache keys
+// and the remote of the local checkout pointing to the repository, if any.
+// An empty repository key disables the cache across sessions.
+func (c *CachingClient) resolveCacheKeys(ctx context.Context) (string, string) {
+	c.keysMu.Lock()
+	defer c.keysMu.Unlock()
+
+	if c.keysDone || c.cache == nil {
+		return c.repoKey, c.remote
+	}
+
+	resolved, err := ResolveRepo(ctx, c.repo)
+	if err != nil {
+		// retried by the next call
+		return "", ""
+	}
+	c.repoKey = resolved.Host + "/" + resolved.Owner + "/" + resolved.Name
+
+	if c.repo.IsLocal() {
+		if remote, err := FindRemote(ctx, resolved.Owner+"/"+resolved.Name); err == nil {
+			c.remote = remote
+		}
+	}
+	c.keysDone = true
+
+	return c.repoKey, c.remote
+}
+
+func (c *CachingClient) ListWorkflows(ctx context.Context, all bool) ([]GhWorkflow, error) {
+	return c.workflows.get(ctx, all, func() ([]GhWorkflow, error) {
+		return c.listWorkflows(ctx, all)
+	})
+}
+
+// listWorkflows gets the workflows from the cache or from the client.
+func (c *CachingClient) listWorkflows(ctx context.Context, all bool) ([]GhWorkflow, error) {
+	repoKey, _ := c.resolveCacheKeys(ctx)
+	key := "workflows:" + repoKey
+	if all {
+		key += ":all"
+	}
+
+	var workflows []GhWorkflow
+	if repoKey != "" && c.cache.Get(key, workflowsTTL, &workflows) && len(workflows) > 0 {
+		// the repository is not stored
+		for i := range workflows {
+			workflows[i].Repo = c.repo
+		}
+		return workflows, nil
+	}
+
+	workflows, err := c.Client.ListWorkflows(ctx, all)
+	if err == nil && repoKey != "" {
+		c.cache.Put(key, workflows)
+	}
+	return workflows, err
+}
+
+func (c *CachingClient) GetWorkflowFile(ctx context.Context, w GhWorkflow, ref string) ([]byte, error) {
+	return c.files.get(ctx, workflowFileKey{Id: string(w.Id), Ref: ref}, func() ([]byte, error) {
+		return c.getWorkflowFile(ctx, w, ref)
+	})
+}
+
+// getWorkflowFile gets the workflow file from the cache or from the client.
+// In the local checkout, the branch may just have been pushed, so the file is keyed
+// by the commit of ref on the remote and never expires. It is not cached across sessions
+// when the commit cannot be resolved. Other repositories key it by ref and path
+// and it expires after workflowFileTTL.
+func (c *CachingClient) getWorkflowFile(ctx context.Context, w GhWorkflow, ref string) ([]byte, error) {
+	repoKey, remote := c.resolveCacheKeys(ctx)
+	if repoKey == "" {
+		return c.Client.GetWorkflowFile(ctx, w, ref)
+	}
+
+	key, ttl := "file:"+repoKey+":"+ref+":"+w.Path, workflowFileTTL
+	if c.repo.IsLocal() {
+		sha, err := c.refShas.get(ctx, ref, func() (string, error) {
+			if remote == "" {
+				return "", nil
+			}
+			return c.refSha(ctx, remote, ref)
+		})
+		if err != nil || sha == "" {
+			return c.Client.GetWorkflowFile(ctx, w, ref)
+		}
+		key, ttl = "commit:"+repoKey+":"+sha+":"+w.Path, 0
+	}
+
+	var src []byte
+	if c.cache.Get(key, ttl, &src) {
+		return src, nil
+	}
+
+	src, err := c.Client.GetWorkflowFile(ctx, w, ref)
+	if err == nil {
+		c.cache.Put(key, src)
+	}
+	return src, err
+}
+
+func (c *CachingClient) ListEnvironments(ctx context.Context) ([]string, error) {
+	return c.environments.get(ctx, struct{}{}, func() ([]string, error) {
+		return c.listEnvironments(ctx)
+	})
+}
+
+// listEnvironments gets the environment names from the cache or from the client.
+func (c *CachingClient) listEnvironments(ctx context.Context) ([]string, error) {
+	repoKey, _ := c.resolveCacheKeys(ctx)
+	key := "environments:" + repoKey
+
+	var names []string
+	if repoKey != "" && c.cache.Get(key, environmentsTTL, &names) {
+		return names, nil
+	}
+
+	names, err := c.Client.ListEnvironments(ctx)
+	if err == nil && repoKey != "" {
+		c.cache.Put(key, names)
+	}
+	return names, err
+}
+
+// memo remembers the results of calls by key. Concurrent calls with the same key
+// wait for the first one. Only successes and ErrNotFound are remembered,
+// so that a failed or canceled background call does not fail the next ones.
+type memo[K comparable, V any] struct {
+	mu      sync.Mutex
+	results map[K]*memoResult[V]
+}
+
+// memoResult is the result of a call, ready is closed once it is set.
+type memoResult[V any] struct {
+	ready chan struct{}
+	value V
+	err   error
+	// canceled is true when the context of the call was done before it returned.
+	canceled bool
+}
+
+// get returns the result of f for key, calling it if needed.
+func (m *memo[K, V]) get(ctx context.Context, key K, f func() (V, error)) (V, error) {
+	for {
+		m.mu.Lock()
+		if m.results == nil {
+			m.results = map[K]*memoResult[V]{}
+		}
+		r, ok := m.results[key]
+		if !ok {
+			r = &memoResult[V]{ready: make(chan struct{})}
+			m.results[key] = r
+		}
+		m.mu.Unlock()
+
+		if !ok {
+			r.value, r.err = f()
+			// killed processes fail with their exit status, the context tells why
+			r.canceled = ctx.Err() != nil
+			if r.canceled || (r.err != nil && !errors.Is(r.err, ErrNotFound)) {
+				m.mu.Lock()
+				delete(m.results, key)
+				m.mu.Unlock()
+			}
+			close(r.ready)
+			return r.value, r.err
+		}
+
+		select {
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		case <-r.ready:
+		}
+
+		// the call was canceled by its own context, try again with ours
+		if r.canceled && ctx.Err() == nil {
+			continue
+		}
+		return r.value, r.err
+	}
+}
@@ -0,0 +1,227 @@
+package subproc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingClient counts the workflow files got from FakeClient.
+// If err is set, getting them fails with it.
+type countingClient struct {
+	FakeClient
+	mu    sync.Mutex
+	count int
+	err   error
+}
+
+func (c *countingClient) GetWorkflowFile(ctx context.Context, w GhWorkflow, ref string) ([]byte, error) {
+	c.mu.Lock()
+	c.count++
+	err := c.err
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return c.FakeClient.GetWorkflowFile(ctx, w, ref)
+}
+
+func TestCachingClient(t *testing.T) {
+	w := GhWorkflow{Id: "1", Path: ".github/workflows/test.yml"}
+	fake := &countingClient{FakeClient: FakeClient{
+		Files: map[FakeFileKey][]byte{{Path: w.Path, Ref: "main"}: []byte("on: workflow_dispatch\n")},
+	}}
+	c := NewCachingClient(fake, Repo{}, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetWorkflowFile(context.Background(), w, "main"); err != nil {
+				t.Errorf("Unexpected error: %s\n", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// a missing file is cached too
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetWorkflowFile(context.Background(), w, "feature"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected is %v but got %v\n", ErrNotFound, err)
+		}
+	}
+
+	if fake.count != 2 {
+		t.Errorf("Expected is %d but got %d\n", 2, fake.count)
+	}
+
+	// other errors, e.g. of a prefetch, are retried by the next call
+	fake.err = &GhError{Message: "Server Error", Status: 502}
+	if _, err := c.GetWorkflowFile(context.Background(), w, "release"); err == nil {
+		t.Errorf("Expected error but got nil\n")
+	}
+	fake.err = nil
+	fake.Files[FakeFileKey{Path: w.Path, Ref: "release"}] = []byte("on: workflow_dispatch\n")
+	if _, err := c.GetWorkflowFile(context.Background(), w, "release"); err != nil {
+		t.Errorf("Unexpected error: %s\n", err)
+	}
+
+	if fake.count != 4 {
+		t.Errorf("Expected is %d but got %d\n", 4, fake.count)
+	}
+}
+
+// mapCache is a Cache in memory.
+type mapCache map[string]any
+
+func (m mapCache) Get(key string, ttl time.Duration, v any) bool {
+	value, ok := m[key]
+	if !ok {
+		return false
+	}
+	reflect.ValueOf(v).Elem().Set(reflect.ValueOf(value))
+	return true
+}
+
+func (m mapCache) Put(key string, v any) {
+	m[key] = v
+}
+
+func TestCachingClientAcrossSessions(t *testing.T) {
+	repo := Repo{Owner: "t4kamura", Name: "infra"}
+	w := GhWorkflow{Id: "1", Name: "Deploy", Path: ".github/workflows/deploy.yml", Status: "active", Repo: repo}
+	fake := &countingClient{FakeClient: FakeClient{
+		Workflows:    []GhWorkflow{w},
+		Files:        map[FakeFileKey][]byte{{Path: w.Path, Ref: "v1.0.0"}: []byte("on: workflow_dispatch\n")},
+		Environments: []string{"production"},
+	}}
+	cache := mapCache{}
+
+	for i := 0; i < 2; i++ {
+		c := NewCachingClient(fake, repo, cache)
+
+		workflows, err := c.ListWorkflows(context.Background(), false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+		if !reflect.DeepEqual(workflows, []GhWorkflow{w}) {
+			t.Errorf("Expected is %v but got %v\n", []GhWorkflow{w}, workflows)
+		}
+
+		if _, err := c.GetWorkflowFile(context.Background(), w, "v1.0.0"); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+
+		envs, err := c.ListEnvironments(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+		if !reflect.DeepEqual(envs, []string{"production"}) {
+			t.Errorf("Expected is %v but got %v\n", []string{"production"}, envs)
+		}
+	}
+
+	// the second session is served from the cache
+	if fake.count != 1 {
+		t.Errorf("Expected is %d but got %d\n", 1, fake.count)
+	}
+	if _, ok := cache["workflows:/t4kamura/infra"]; !ok {
+		t.Errorf("Expected the workflows to be cached but got %v\n", cache)
+	}
+}
+
+func TestMemoCanceled(t *testing.T) {
+	var m memo[string, int]
+	calls := 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the result of a canceled call is not remembered
+	if _, err := m.get(ctx, "key", func() (int, error) { calls++; return 0, ctx.Err() }); err == nil {
+		t.Errorf("Expected error but got nil\n")
+	}
+
+	v, err := m.get(context.Background(), "key", func() (int, error) { calls++; return 42, nil })
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if v != 42 {
+		t.Errorf("Expected is %d but got %d\n", 42, v)
+	}
+
+	// the result of a successful call is remembered
+	if v, _ := m.get(context.Background(), "key", func() (int, error) { calls++; return 0, nil }); v != 42 {
+		t.Errorf("Expected is %d but got %d\n", 42, v)
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected is %d but got %d\n", 2, calls)
+	}
+}
+
+func TestCachingClientAfterPush(t *testing.T) {
+	w := GhWorkflow{Id: "1", Path: ".github/workflows/deploy.yml"}
+	fake := &countingClient{FakeClient: FakeClient{
+		Files: map[FakeFileKey][]byte{{Path: w.Path, Ref: "feature"}: []byte("on: workflow_dispatch\n")},
+	}}
+	cache := mapCache{}
+	sha := "1111111"
+
+	newClient := func() *CachingClient {
+		c := NewCachingClient(fake, Repo{}, cache)
+		// the local checkout, without calling gh and git
+		c.keysDone, c.repoKey, c.remote = true, "/t4kamura/gh-wrun", "origin"
+		c.refSha = func(ctx context.Context, remote, ref string) (string, error) { return sha, nil }
+		return c
+	}
+
+	if _, err := newClient().GetWorkflowFile(context.Background(), w, "feature"); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	// edit the workflow, then push it
+	edited := []byte("on:\n  workflow_dispatch:\n    inputs:\n      env:\n        type: string\n")
+	fake.Files[FakeFileKey{Path: w.Path, Ref: "feature"}] = edited
+	sha = "2222222"
+
+	c := newClient()
+	src, err := c.GetWorkflowFile(context.Background(), w, "feature")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if string(src) != string(edited) {
+		t.Errorf("Expected is %s but got %s\n", edited, src)
+	}
+	if err := c.Dispatch(context.Background(), w, "feature", []struct{ Key, Value string }{{"env", "staging"}}); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if len(fake.Dispatches) != 1 || fake.Dispatches[0].Inputs[0].Key != "env" {
+		t.Errorf("Expected the env input to be dispatched but got %v\n", fake.Dispatches)
+	}
+
+	// the same commit is served from the cache
+	if _, err := newClient().GetWorkflowFile(context.Background(), w, "feature"); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if fake.count != 2 {
+		t.Errorf("Expected is %d but got %d\n", 2, fake.count)
+	}
+
+	// the file is not cached across sessions when the commit cannot be resolved
+	c = newClient()
+	c.remote = ""
+	if _, err := c.GetWorkflowFile(context.Background(), w, "feature"); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if _, err := newClient().GetWorkflowFile(context.Background(), w, "feature"); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if fake.count != 3 {
+		t.Errorf("Expected is %d but got %d\n", 3, fake.count)
+	}
+}
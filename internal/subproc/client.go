@@ -0,0 +1,142 @@
+package subproc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Client is the access to GitHub needed to dispatch workflows.
+type Client interface {
+	// ListWorkflows returns the active workflows, and the disabled ones if all is true.
+	ListWorkflows(ctx context.Context, all bool) ([]GhWorkflow, error)
+	// EnableWorkflow enables the disabled workflow.
+	EnableWorkflow(ctx context.Context, w GhWorkflow) error
+	// GetWorkflowFile returns the content of the workflow file on ref.
+	// If ref is empty, the default branch is used.
+	GetWorkflowFile(ctx context.Context, w GhWorkflow, ref string) ([]byte, error)
+	// ListEnvironments returns the GitHub Environments names.
+	ListEnvironments(ctx context.Context) ([]string, error)
+	// Dispatch creates a workflow_dispatch event for the workflow on ref.
+	Dispatch(ctx context.Context, w GhWorkflow, ref string, inputs []struct{ Key, Value string }) error
+	// ListDispatchRuns returns recent workflow_dispatch runs of the workflow
+	// on ref, triggered by actor if not empty.
+	ListDispatchRuns(ctx context.Context, w GhWorkflow, ref, actor string) ([]GhRun, error)
+}
+
+// workflowListLimit is the maximum number of workflows listed,
+// gh workflow list returns only 50 by default.
+const workflowListLimit = "1000"
+
+// GhClient is a Client running gh subcommands.
+type GhClient struct {
+	Repo Repo
+}
+
+// NewGhClient returns a Client running gh subcommands against repo.
+func NewGhClient(repo Repo) *GhClient {
+	return &GhClient{Repo: repo}
+}
+
+func (c *GhClient) ListWorkflows(ctx context.Context, all bool) ([]GhWorkflow, error) {
+	args := []string{"workflow", "list", "--json", "id,name,path,state", "--limit", workflowListLimit}
+	if all {
+		args = append(args, "-a")
+	}
+	out, err := c.Repo.gh(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var workflows []GhWorkflow
+	if err := json.Unmarshal(out, &workflows); err != nil {
+		return nil, err
+	}
+
+	if len(workflows) == 0 {
+		return nil, errors.New("No workflows found")
+	}
+
+	for i := range workflows {
+		workflows[i].Repo = c.Repo
+	}
+
+	return workflows, nil
+}
+
+func (c *GhClient) EnableWorkflow(ctx context.Context, w GhWorkflow) error {
+	_, err := c.Repo.gh(ctx, "workflow", "enable", string(w.Id))
+	return err
+}
+
+func (c *GhClient) GetWorkflowFile(ctx context.Context, w GhWorkflow, ref string) ([]byte, error) {
+	args := []string{"workflow", "view", string(w.Id), "-y"}
+	if ref != "" {
+		args = append(args, "-r", ref)
+	}
+	return c.Repo.gh(ctx, args...)
+}
+
+func (c *GhClient) ListEnvironments(ctx context.Context) ([]string, error) {
+	repoWithOwner, err := GetRepositoryWithOwner(ctx, c.Repo)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("/repos/%s/environments", repoWithOwner)
+
+	out, err := c.Repo.ghApi(ctx, "-H", "Accept: application/vnd.github+json", "-H", "X-GitHub-Api-Version: 2022-11-28", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var environmentsResult GhApiGetEnvironmentsResult
+	if err := json.Unmarshal(out, &environmentsResult); err != nil {
+		return nil, err
+	}
+
+	environments := make([]string, 0, environmentsResult.TotalCount)
+	for _, e := range environmentsResult.Environments {
+		environments = append(environments, e.Name)
+	}
+	return environments, nil
+}
+
+func (c *GhClient) Dispatch(ctx context.Context, w GhWorkflow, ref string, inputs []struct{ Key, Value string }) error {
+	_, err := c.Repo.gh(ctx, dispatchArgs(w, ref, inputs)...)
+	return err
+}
+
+// dispatchArgs returns the gh arguments dispatching the workflow, without the repository.
+func dispatchArgs(w GhWorkflow, ref string, inputs []struct{ Key, Value string }) []string {
+	args := []string{"workflow", "run", string(w.Id), "-r", ref}
+	for _, m := range inputs {
+		args = append(args, "-f", m.Key+"="+m.Value)
+	}
+	return args
+}
+
+func (c *GhClient) ListDispatchRuns(ctx context.Context, w GhWorkflow, ref, actor string) ([]GhRun, error) {
+	args := []string{
+		"run", "list",
+		"--workflow", string(w.Id),
+		"--branch", ref,
+		"--event", "workflow_dispatch",
+		"--limit", "20",
+		"--json", "databaseId,number,status,conclusion,url,headBranch,createdAt",
+	}
+	if actor != "" {
+		args = append(args, "--user", actor)
+	}
+
+	out, err := c.Repo.gh(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []GhRun
+	if err := json.Unmarshal(out, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
@@ -0,0 +1,58 @@
+package subproc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// shellSafe matches words that need no quoting in a POSIX shell.
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// ShellQuote joins args into a command line safe to paste in a POSIX shell.
+func ShellQuote(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, a := range args {
+		if shellSafe.MatchString(a) {
+			quoted = append(quoted, a)
+		} else {
+			quoted = append(quoted, "'"+strings.ReplaceAll(a, "'", `'"'"'`)+"'")
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// DispatchCommand returns the gh command line dispatching the workflow on ref.
+func DispatchCommand(w GhWorkflow, ref string, inputs []struct{ Key, Value string }) string {
+	args := append([]string{"gh"}, dispatchArgs(w, ref, inputs)...)
+	return ShellQuote(append(args, w.Repo.repoArgs()...))
+}
+
+// EnableCommand returns the gh command line enabling the disabled workflow.
+func EnableCommand(w GhWorkflow) string {
+	args := []string{"gh", "workflow", "enable", string(w.Id)}
+	return ShellQuote(append(args, w.Repo.repoArgs()...))
+}
+
+// DispatchCurl returns the curl command line dispatching the workflow on ref
+// through the REST API. The token is read from gh when the command is run.
+// repo is the repository of the workflow resolved by ResolveRepo.
+func DispatchCurl(repo Repo, w GhWorkflow, ref string, inputs []struct{ Key, Value string }) (string, error) {
+	payload, err := dispatchPayload(ref, inputs)
+	if err != nil {
+		return "", err
+	}
+
+	tokenArgs := "gh auth token"
+	if repo.Host != "" {
+		tokenArgs += " --hostname " + ShellQuote([]string{repo.Host})
+	}
+
+	return fmt.Sprintf("curl -X POST -H %s -H %s -H \"Authorization: Bearer $(%s)\" %s -d %s",
+		ShellQuote([]string{"Accept: application/vnd.github+json"}),
+		ShellQuote([]string{"X-GitHub-Api-Version: 2022-11-28"}),
+		tokenArgs,
+		ShellQuote([]string{apiBaseURL(repo.Host) + "/repos/" + repo.Owner + "/" + repo.Name + dispatchPath(w)}),
+		ShellQuote([]string{string(payload)}),
+	), nil
+}
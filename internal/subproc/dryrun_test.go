@@ -0,0 +1,92 @@
+package subproc
+
+import (
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "safe", args: []string{"gh", "workflow", "run", "-f", "env=prod"}, want: "gh workflow run -f env=prod"},
+		{name: "space", args: []string{"-f", "message=hello world"}, want: "-f 'message=hello world'"},
+		{name: "single quote", args: []string{"-f", "message=it's"}, want: `-f 'message=it'"'"'s'`},
+		{name: "empty", args: []string{"-f", ""}, want: "-f ''"},
+		{name: "shell chars", args: []string{"$(rm -rf /)"}, want: "'$(rm -rf /)'"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := ShellQuote(test.args)
+			if got != test.want {
+				t.Errorf("Expected is %s but got %s\n", test.want, got)
+			}
+		})
+	}
+}
+
+func TestDispatchCommand(t *testing.T) {
+	inputs := []struct{ Key, Value string }{
+		{Key: "env", Value: "prod"},
+		{Key: "message", Value: "hello world"},
+	}
+
+	testCases := []struct {
+		name     string
+		workflow GhWorkflow
+		want     string
+	}{
+		{
+			name:     "local repository",
+			workflow: GhWorkflow{Id: "123"},
+			want:     "gh workflow run 123 -r main -f env=prod -f 'message=hello world'",
+		},
+		{
+			name:     "other repository",
+			workflow: GhWorkflow{Id: "123", Repo: Repo{Owner: "t4kamura", Name: "infra"}},
+			want:     "gh workflow run 123 -r main -f env=prod -f 'message=hello world' -R t4kamura/infra",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := DispatchCommand(test.workflow, "main", inputs)
+			if got != test.want {
+				t.Errorf("Expected is %s but got %s\n", test.want, got)
+			}
+		})
+	}
+}
+
+func TestDispatchCurl(t *testing.T) {
+	// a local repository resolved to its host
+	w := GhWorkflow{Id: "123"}
+	repo := Repo{Host: "github.example.com", Owner: "t4kamura", Name: "infra"}
+	inputs := []struct{ Key, Value string }{{Key: "env", Value: "prod"}}
+
+	got, err := DispatchCurl(repo, w, "main", inputs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+
+	want := `curl -X POST -H 'Accept: application/vnd.github+json' -H 'X-GitHub-Api-Version: 2022-11-28' ` +
+		`-H "Authorization: Bearer $(gh auth token --hostname github.example.com)" ` +
+		`https://github.example.com/api/v3/repos/t4kamura/infra/actions/workflows/123/dispatches ` +
+		`-d '{"ref":"main","inputs":{"env":"prod"}}'`
+
+	if got != want {
+		t.Errorf("Expected is %s but got %s\n", want, got)
+	}
+}
+
+func TestEnableCommand(t *testing.T) {
+	w := GhWorkflow{Id: "123", Repo: Repo{Owner: "t4kamura", Name: "infra"}}
+
+	got := EnableCommand(w)
+	want := "gh workflow enable 123 -R t4kamura/infra"
+	if got != want {
+		t.Errorf("Expected is %s but got %s\n", want, got)
+	}
+}
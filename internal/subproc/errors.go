@@ -0,0 +1,155 @@
+package subproc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kinds of GhError, matched with errors.Is.
+var (
+	ErrNotAuthenticated = errors.New("Not authenticated")
+	ErrPermission       = errors.New("Missing permission")
+	ErrWorkflowNotOnRef = errors.New("Workflow not dispatchable on the ref")
+	ErrUnknownInput     = errors.New("Unknown workflow input")
+	ErrNotFound         = errors.New("Not found")
+)
+
+// ghExitAuth is the exit status of gh when it has no credentials.
+const ghExitAuth = 4
+
+// GhError is a failed gh or git call, or a failed GitHub API request.
+type GhError struct {
+	// Message is the message of the GitHub API, or the error output of the call.
+	Message string
+	// Status is the HTTP status of the GitHub API response, 0 if unknown.
+	Status           int
+	DocumentationURL string
+	// Kind is one of the Err* kinds, nil if the error is not recognized.
+	Kind error
+	// Hint tells how to fix the error, if known.
+	Hint string
+
+	err error
+}
+
+func (e *GhError) Error() string {
+	msg := e.Message
+	if e.Status != 0 {
+		msg = fmt.Sprintf("%s (HTTP %d)", msg, e.Status)
+	}
+	if e.Hint != "" {
+		msg += "\n" + e.Hint
+	}
+	if e.DocumentationURL != "" {
+		msg += "\nSee " + e.DocumentationURL
+	}
+	return msg
+}
+
+func (e *GhError) Unwrap() []error {
+	errs := []error{}
+	for _, err := range []error{e.Kind, e.err} {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// ghHTTPError matches the API errors printed by gh subcommands,
+// e.g. "HTTP 422: Unexpected inputs provided: [\"foo\"] (https://api.github.com/...)".
+var ghHTTPError = regexp.MustCompile(`HTTP (\d{3}): (.+?)(?: \(https?://\S+\))?$`)
+
+// ghApiError matches the API errors printed by gh api, e.g. "gh: Not Found (HTTP 404)".
+var ghApiError = regexp.MustCompile(`^gh: (.+) \(HTTP (\d{3})\)$`)
+
+// commandError returns err of a failed call as a *GhError.
+// out is the standard output, where gh api writes the body of API errors.
+// Errors other than exit statuses, e.g. a missing executable, are returned as is.
+func commandError(out []byte, err error) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+
+	e := &GhError{err: err}
+	stderr := strings.TrimSpace(string(exitErr.Stderr))
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimSpace(line)
+		if m := ghHTTPError.FindStringSubmatch(line); m != nil {
+			e.Status, _ = strconv.Atoi(m[1])
+			e.Message = m[2]
+			break
+		}
+		if m := ghApiError.FindStringSubmatch(line); m != nil {
+			e.Status, _ = strconv.Atoi(m[2])
+			e.Message = m[1]
+			break
+		}
+	}
+	if e.Status != 0 {
+		parseApiErrorBody(e, out)
+	} else if stderr != "" {
+		e.Message = stderr
+	} else {
+		e.Message = err.Error()
+	}
+
+	if exitErr.ExitCode() == ghExitAuth || strings.Contains(stderr, "gh auth login") {
+		e.Kind = ErrNotAuthenticated
+	}
+	classify(e)
+	return e
+}
+
+// apiError returns the failed GitHub API response with status and body as a *GhError.
+func apiError(status int, body []byte) error {
+	e := &GhError{Status: status, Message: "GitHub API error"}
+	parseApiErrorBody(e, body)
+	classify(e)
+	return e
+}
+
+// parseApiErrorBody sets the message of the GitHub API error body to e, if any.
+func parseApiErrorBody(e *GhError, body []byte) {
+	var res GhApiGetEnvironmentsFailedResult
+	if err := json.Unmarshal(body, &res); err != nil || res.Message == "" {
+		return
+	}
+	e.Message = res.Message
+	e.DocumentationURL = res.DocumentationURL
+}
+
+// classify sets the kind and the hint of e from its status and message.
+func classify(e *GhError) {
+	msg := strings.ToLower(e.Message)
+	switch {
+	case e.Kind == ErrNotAuthenticated || e.Status == 401:
+		e.Kind = ErrNotAuthenticated
+		e.Hint = "Run gh auth login, or set GH_TOKEN, to authenticate gh."
+	case strings.Contains(msg, "unexpected inputs"):
+		e.Kind = ErrUnknownInput
+		e.Hint = "The workflow on the ref does not declare these inputs, check the input names or the ref."
+	case strings.Contains(msg, "no ref found"):
+		e.Kind = ErrWorkflowNotOnRef
+		e.Hint = "Push the ref to GitHub, or pick another one."
+	case strings.Contains(msg, "try specifying a different ref"):
+		// gh workflow view on a ref without the workflow file
+		e.Kind = ErrNotFound
+		e.Hint = "The workflow file does not exist on the ref, push it or pick another ref."
+	case strings.Contains(msg, "workflow_dispatch"):
+		e.Kind = ErrWorkflowNotOnRef
+		e.Hint = "The workflow file on the ref must exist and have a workflow_dispatch trigger, push it or pick another ref."
+	case e.Status == 403:
+		e.Kind = ErrPermission
+		e.Hint = "Dispatching needs write access to the repository and a token with the workflow scope, e.g. gh auth refresh -s workflow."
+	case e.Status == 404:
+		e.Kind = ErrNotFound
+		e.Hint = "Check the repository and workflow, GitHub also answers 404 when the token cannot access the repository."
+	}
+}
@@ -0,0 +1,117 @@
+package subproc
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestCommandError(t *testing.T) {
+	testCases := []struct {
+		name       string
+		stdout     string
+		stderr     string
+		wantKind   error
+		wantStatus int
+		wantMsg    string
+	}{
+		{
+			name:       "unexpected inputs",
+			stderr:     "could not create workflow dispatch event: HTTP 422: Unexpected inputs provided: [\"foo\"] (https://api.github.com/repos/t4kamura/gh-wrun/actions/workflows/1/dispatches)\n",
+			wantKind:   ErrUnknownInput,
+			wantStatus: 422,
+			wantMsg:    "Unexpected inputs provided: [\"foo\"]",
+		},
+		{
+			name:       "no ref",
+			stderr:     "could not create workflow dispatch event: HTTP 422: No ref found for: feature (https://api.github.com/repos/t4kamura/gh-wrun/actions/workflows/1/dispatches)\n",
+			wantKind:   ErrWorkflowNotOnRef,
+			wantStatus: 422,
+			wantMsg:    "No ref found for: feature",
+		},
+		{
+			name:       "no workflow_dispatch trigger",
+			stderr:     "could not create workflow dispatch event: HTTP 422: Workflow does not have 'workflow_dispatch' trigger (https://api.github.com/repos/t4kamura/gh-wrun/actions/workflows/1/dispatches)\n",
+			wantKind:   ErrWorkflowNotOnRef,
+			wantStatus: 422,
+			wantMsg:    "Workflow does not have 'workflow_dispatch' trigger",
+		},
+		{
+			name:       "forbidden",
+			stderr:     "could not create workflow dispatch event: HTTP 403: Resource not accessible by integration (https://api.github.com/repos/t4kamura/gh-wrun/actions/workflows/1/dispatches)\n",
+			wantKind:   ErrPermission,
+			wantStatus: 403,
+			wantMsg:    "Resource not accessible by integration",
+		},
+		{
+			name:     "not logged in",
+			stderr:   "To get started with GitHub CLI, please run:  gh auth login\n",
+			wantKind: ErrNotAuthenticated,
+			wantMsg:  "To get started with GitHub CLI, please run:  gh auth login",
+		},
+		{
+			name:       "gh api body",
+			stdout:     `{"message":"Not Found","documentation_url":"https://docs.github.com/rest","status":"404"}`,
+			stderr:     "gh: Not Found (HTTP 404)\n",
+			wantKind:   ErrNotFound,
+			wantStatus: 404,
+			wantMsg:    "Not Found",
+		},
+		{
+			name:     "workflow file not on ref",
+			stderr:   "could not find workflow file .github/workflows/deploy.yml on feature, try specifying a different ref\n",
+			wantKind: ErrNotFound,
+			wantMsg:  "could not find workflow file .github/workflows/deploy.yml on feature, try specifying a different ref",
+		},
+		{
+			name:    "git",
+			stderr:  "fatal: not a git repository (or any of the parent directories): .git\n",
+			wantMsg: "fatal: not a git repository (or any of the parent directories): .git",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := commandError([]byte(test.stdout), &exec.ExitError{Stderr: []byte(test.stderr)})
+
+			var ghErr *GhError
+			if !errors.As(err, &ghErr) {
+				t.Fatalf("Expected *GhError but got %T\n", err)
+			}
+			if ghErr.Kind != test.wantKind {
+				t.Errorf("Expected is %v but got %v\n", test.wantKind, ghErr.Kind)
+			}
+			if ghErr.Status != test.wantStatus {
+				t.Errorf("Expected is %d but got %d\n", test.wantStatus, ghErr.Status)
+			}
+			if ghErr.Message != test.wantMsg {
+				t.Errorf("Expected is %s but got %s\n", test.wantMsg, ghErr.Message)
+			}
+			if test.wantKind != nil && !errors.Is(err, test.wantKind) {
+				t.Errorf("Expected is %v but got %v\n", test.wantKind, err)
+			}
+
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				t.Errorf("Expected the exit error to be kept\n")
+			}
+		})
+	}
+}
+
+func TestApiError(t *testing.T) {
+	err := apiError(422, []byte(`{"message":"Unexpected inputs provided: [\"foo\"]","documentation_url":"https://docs.github.com/rest/actions/workflows#create-a-workflow-dispatch-event"}`))
+	if !errors.Is(err, ErrUnknownInput) {
+		t.Errorf("Expected is %v but got %v\n", ErrUnknownInput, err)
+	}
+
+	err = apiError(401, []byte(`{"message":"Bad credentials"}`))
+	if !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("Expected is %v but got %v\n", ErrNotAuthenticated, err)
+	}
+
+	want := "GitHub API error (HTTP 500)"
+	if got := apiError(500, []byte("<html>")).Error(); got != want {
+		t.Errorf("Expected is %s but got %s\n", want, got)
+	}
+}
@@ -0,0 +1,101 @@
+package subproc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultCallTimeout is the time limit of a call when none is set, see WithCallTimeout.
+	DefaultCallTimeout = 30 * time.Second
+	// killDelay is how long an interrupted process has to exit before it is killed.
+	killDelay = 3 * time.Second
+)
+
+type callTimeoutKey struct{}
+
+// WithCallTimeout returns a copy of ctx limiting every gh, git and API call to d.
+// A zero d disables the limit. Calls waiting on the user, like gh run watch, are not limited.
+func WithCallTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, d)
+}
+
+// callTimeout returns the call timeout set in ctx, zero if disabled.
+func callTimeout(ctx context.Context) time.Duration {
+	d, ok := ctx.Value(callTimeoutKey{}).(time.Duration)
+	if !ok {
+		return DefaultCallTimeout
+	}
+	return max(d, 0)
+}
+
+// callContext returns ctx limited to the call timeout set in ctx.
+func callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	d := callTimeout(ctx)
+	if d == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// interruptibleCommand returns the command name with args, interrupted when ctx is done
+// and killed if it has not exited after killDelay.
+func interruptibleCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error {
+		// the process is killed after WaitDelay where interrupts are not supported
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = killDelay
+	return cmd
+}
+
+// output runs the command name with args, limited by the call timeout, and returns its standard output.
+// A failure is returned as a *GhError carrying the error output of the command,
+// and a timeout as a *TimeoutError.
+func output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	callCtx, cancel := callContext(ctx)
+	defer cancel()
+
+	out, err := interruptibleCommand(callCtx, name, args...).Output()
+	if err == nil {
+		return out, nil
+	}
+	// killed processes fail with their exit status, the context tells why
+	if ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return out, &TimeoutError{Call: commandName(name, args), Timeout: callTimeout(ctx)}
+	}
+	return out, commandError(out, err)
+}
+
+// commandName returns the command name with its leading subcommands, e.g. "gh workflow list".
+func commandName(name string, args []string) string {
+	words := []string{name}
+	for _, a := range args {
+		if len(words) == 3 || strings.HasPrefix(a, "-") {
+			break
+		}
+		words = append(words, a)
+	}
+	return strings.Join(words, " ")
+}
+
+// TimeoutError is a call stopped by its call timeout, see WithCallTimeout.
+type TimeoutError struct {
+	// Call describes the call, e.g. "gh workflow list".
+	Call    string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s, see --timeout", e.Call, e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
@@ -0,0 +1,88 @@
+package subproc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCallContext(t *testing.T) {
+	testCases := []struct {
+		name         string
+		ctx          context.Context
+		wantDeadline bool
+		wantTimeout  time.Duration
+	}{
+		{name: "default", ctx: context.Background(), wantDeadline: true, wantTimeout: DefaultCallTimeout},
+		{name: "set", ctx: WithCallTimeout(context.Background(), time.Minute), wantDeadline: true, wantTimeout: time.Minute},
+		{name: "disabled", ctx: WithCallTimeout(context.Background(), 0), wantDeadline: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := callContext(test.ctx)
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if ok != test.wantDeadline {
+				t.Fatalf("Expected deadline is %v but got %v\n", test.wantDeadline, ok)
+			}
+
+			if ok {
+				if d := time.Until(deadline); d > test.wantTimeout || d < test.wantTimeout-time.Second {
+					t.Errorf("Expected is %s but got %s\n", test.wantTimeout, d)
+				}
+			}
+		})
+	}
+}
+
+func TestOutputTimeout(t *testing.T) {
+	ctx := WithCallTimeout(context.Background(), 50*time.Millisecond)
+
+	start := time.Now()
+	_, err := output(ctx, "sleep", "5")
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected *TimeoutError but got %v\n", err)
+	}
+
+	want := "sleep 5 timed out after 50ms, see --timeout"
+	if err.Error() != want {
+		t.Errorf("Expected is %s but got %s\n", want, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the command to be interrupted but it ran for %s\n", elapsed)
+	}
+
+	// a canceled call is not a timeout
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := output(ctx, "sleep", "5"); errors.As(err, &timeoutErr) {
+		t.Errorf("Expected an error other than a timeout but got %v\n", err)
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "gh", args: []string{"workflow", "list", "--json", "id"}, want: "gh workflow list"},
+		{name: "gh", args: []string{"api", "--hostname", "github.example.com", "user"}, want: "gh api"},
+		{name: "git", args: []string{"remote", "get-url", "origin"}, want: "git remote get-url"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.want, func(t *testing.T) {
+			got := commandName(test.name, test.args)
+			if got != test.want {
+				t.Errorf("Expected is %s but got %s\n", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package subproc
+
+import (
+	"context"
+	"fmt"
+)
+
+// FakeClient is an in-memory Client for tests.
+type FakeClient struct {
+	Workflows []GhWorkflow
+	// Files are the workflow file contents keyed by path and ref.
+	// The empty ref is the default branch.
+	Files        map[FakeFileKey][]byte
+	Environments []string
+	Runs         []GhRun
+	// Dispatches records every Dispatch call.
+	Dispatches []FakeDispatch
+	// Enabled records the workflows enabled by EnableWorkflow.
+	Enabled []GhWorkflow
+}
+
+type FakeFileKey struct {
+	Path, Ref string
+}
+
+type FakeDispatch struct {
+	Workflow GhWorkflow
+	Ref      string
+	Inputs   []struct{ Key, Value string }
+}
+
+func (c *FakeClient) ListWorkflows(ctx context.Context, all bool) ([]GhWorkflow, error) {
+	workflows := []GhWorkflow{}
+	for _, w := range c.Workflows {
+		if all || w.IsActive() {
+			workflows = append(workflows, w)
+		}
+	}
+	return workflows, nil
+}
+
+func (c *FakeClient) EnableWorkflow(ctx context.Context, w GhWorkflow) error {
+	c.Enabled = append(c.Enabled, w)
+	return nil
+}
+
+func (c *FakeClient) GetWorkflowFile(ctx context.Context, w GhWorkflow, ref string) ([]byte, error) {
+	src, ok := c.Files[FakeFileKey{Path: w.Path, Ref: ref}]
+	if !ok {
+		return nil, &GhError{Message: fmt.Sprintf("%s not found on %q", w.Path, ref), Status: 404, Kind: ErrNotFound}
+	}
+	return src, nil
+}
+
+func (c *FakeClient) ListEnvironments(ctx context.Context) ([]string, error) {
+	return c.Environments, nil
+}
+
+func (c *FakeClient) Dispatch(ctx context.Context, w GhWorkflow, ref string, inputs []struct{ Key, Value string }) error {
+	c.Dispatches = append(c.Dispatches, FakeDispatch{Workflow: w, Ref: ref, Inputs: inputs})
+	return nil
+}
+
+func (c *FakeClient) ListDispatchRuns(ctx context.Context, w GhWorkflow, ref, actor string) ([]GhRun, error) {
+	runs := []GhRun{}
+	for _, r := range c.Runs {
+		if r.HeadBranch == ref {
+			runs = append(runs, r)
+		}
+	}
+	return runs, nil
+}
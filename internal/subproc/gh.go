@@ -3,14 +3,19 @@ package subproc
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 
-	"os/exec"
-
-	"gopkg.in/yaml.v2"
+	"github.com/t4kamura/gh-wrun/internal/progress"
+	"gopkg.in/yaml.v3"
 )
 
 type GhWorkflow struct {
@@ -18,6 +23,8 @@ type GhWorkflow struct {
 	Name   string      `json:"name"`
 	Path   string      `json:"path"`
 	Status string      `json:"state"`
+	// Repo is the repository the workflow belongs to.
+	Repo Repo `json:"-"`
 }
 
 type GhWorkflowInput struct {
@@ -29,13 +36,13 @@ type GhWorkflowInput struct {
 	Options  []string
 }
 
-type GhWorkflowInputsYaml struct {
-	Name string
-	On   struct {
-		WorkflowDispatch struct {
-			Inputs yaml.MapSlice
-		} `yaml:"workflow_dispatch"`
-	}
+// GhWorkflowStateActive is the state of a workflow that can be run.
+// Disabled workflows have a state starting with "disabled", e.g. "disabled_manually".
+const GhWorkflowStateActive = "active"
+
+// IsActive reports whether the workflow is enabled.
+func (w GhWorkflow) IsActive() bool {
+	return w.Status == GhWorkflowStateActive
 }
 
 const (
@@ -43,12 +50,12 @@ const (
 	GhWorkflowInputTypeChoice      = "choice"
 	GhWorkflowInputTypeBoolean     = "boolean"
 	GhWorkflowInputTypeEnvironment = "environment"
+	GhWorkflowInputTypeNumber      = "number"
 )
 
 // GetGhVersion returns the version of gh.
-func GetGhVersion() (string, error) {
-	cmd := exec.Command("gh", "version")
-	out, err := cmd.Output()
+func GetGhVersion(ctx context.Context) (string, error) {
+	out, err := output(ctx, "gh", "version")
 	if err != nil {
 		return "", err
 	}
@@ -65,115 +72,268 @@ func GetGhVersion() (string, error) {
 	return words[2], nil
 }
 
-// GetWorkflows returns a list of active workflows.
-func GetWorkflows() ([]GhWorkflow, error) {
-	// if include disabled, add -a flag
-	cmd := exec.Command("gh", "workflow", "list", "--json", "id,name,path,state")
-	out, err := cmd.Output()
+// FindWorkflow returns the workflow matching key.
+// The key is compared with the workflow ID, name, path and file name.
+func FindWorkflow(workflows []GhWorkflow, key string) (GhWorkflow, error) {
+	for _, w := range workflows {
+		if string(w.Id) == key || w.Name == key || w.Path == key || filepath.Base(w.Path) == key {
+			return w, nil
+		}
+	}
+
+	return GhWorkflow{}, fmt.Errorf("Workflow %q not found", key)
+}
+
+// GetWorkflowInputs returns inputs for the workflow on the given ref.
+// If ref is empty, the workflow file on the default branch is used.
+func GetWorkflowInputs(ctx context.Context, c Client, w GhWorkflow, ref string) ([]GhWorkflowInput, error) {
+	src, err := c.GetWorkflowFile(ctx, w, ref)
 	if err != nil {
 		return nil, err
 	}
 
-	var workflows []GhWorkflow
-	if err := json.Unmarshal(out, &workflows); err != nil {
+	return parseWorkflowInputs(src)
+}
+
+// WorkflowParseError is an error in a workflow file at a given line.
+type WorkflowParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *WorkflowParseError) Error() string {
+	return fmt.Sprintf("invalid workflow file, line %d: %s", e.Line, e.Msg)
+}
+
+// yamlSyntaxErrorLine matches the line number in yaml syntax errors.
+var yamlSyntaxErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// GetWorkflowEvents returns the events triggering the workflow on the given ref.
+// If ref is empty, the workflow file on the default branch is used.
+func GetWorkflowEvents(ctx context.Context, c Client, w GhWorkflow, ref string) ([]string, error) {
+	src, err := c.GetWorkflowFile(ctx, w, ref)
+	if err != nil {
 		return nil, err
 	}
 
-	if len(workflows) == 0 {
-		return nil, errors.New("No workflows found")
+	return parseWorkflowEvents(src)
+}
+
+// IsDispatchable reports whether events include workflow_dispatch.
+func IsDispatchable(events []string) bool {
+	for _, e := range events {
+		if e == workflowDispatchEvent {
+			return true
+		}
+	}
+	return false
+}
+
+// workflowDispatchEvent is the event of manually run workflows.
+const workflowDispatchEvent = "workflow_dispatch"
+
+// parseWorkflowEvents returns the event names of the on: trigger of the workflow file.
+func parseWorkflowEvents(src []byte) ([]string, error) {
+	on, err := parseWorkflowOn(src)
+	if err != nil || on == nil {
+		return []string{}, err
 	}
 
-	return workflows, nil
+	events := []string{}
+	switch on.Kind {
+	case yaml.ScalarNode:
+		events = append(events, on.Value)
+	case yaml.SequenceNode:
+		for _, c := range on.Content {
+			events = append(events, resolveYamlAlias(c).Value)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(on.Content); i += 2 {
+			events = append(events, on.Content[i].Value)
+		}
+	default:
+		return nil, &WorkflowParseError{Line: on.Line, Msg: "on must be a string, a list or a mapping"}
+	}
+	return events, nil
 }
 
-// GetWorkflowInputs returns inputs for a workflow.
-func (g *GhWorkflow) GetWorkflowInputs() ([]GhWorkflowInput, error) {
-	//nolint:gosec
-	cmd := exec.Command("gh", "workflow", "view", string(g.Id), "-y")
-	out, err := cmd.Output()
+// parseWorkflowOn parses the workflow file and returns its on: trigger node,
+// or nil if there is none. Invalid files return a *WorkflowParseError.
+func parseWorkflowOn(src []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		if m := yamlSyntaxErrorLine.FindStringSubmatch(err.Error()); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			return nil, &WorkflowParseError{Line: line, Msg: m[2]}
+		}
+		return nil, &WorkflowParseError{Line: 1, Msg: err.Error()}
+	}
 
-	if err != nil {
-		return nil, err
+	if len(doc.Content) == 0 {
+		return nil, &WorkflowParseError{Line: 1, Msg: "empty workflow file"}
 	}
 
-	w, err := parseWorkflowInputs(out)
-	if err != nil {
-		return nil, err
+	root := resolveYamlAlias(doc.Content[0])
+	if root.Kind != yaml.MappingNode {
+		return nil, &WorkflowParseError{Line: root.Line, Msg: "workflow must be a mapping"}
 	}
 
-	return w, nil
+	return yamlMapValue(root, "on"), nil
 }
 
 // parseWorkflowInputs parses the output from gh workflow view.
+// Every valid shape of the on: trigger is accepted (string, list or map),
+// and scalar values are coerced to the expected type.
+// Invalid files return a *WorkflowParseError.
 func parseWorkflowInputs(src []byte) ([]GhWorkflowInput, error) {
-	var w []GhWorkflowInput
-	r := GhWorkflowInputsYaml{}
+	on, err := parseWorkflowOn(src)
+	if err != nil {
+		return nil, err
+	}
+	if on == nil {
+		return []GhWorkflowInput{}, nil
+	}
+
+	// on: workflow_dispatch and on: [push, workflow_dispatch] have no inputs
+	switch on.Kind {
+	case yaml.ScalarNode, yaml.SequenceNode:
+		return []GhWorkflowInput{}, nil
+	case yaml.MappingNode:
+	default:
+		return nil, &WorkflowParseError{Line: on.Line, Msg: "on must be a string, a list or a mapping"}
+	}
 
-	if err := yaml.Unmarshal(src, &r); err != nil {
-		return w, err
+	dispatch := yamlMapValue(on, workflowDispatchEvent)
+	if dispatch == nil || isYamlNull(dispatch) {
+		return []GhWorkflowInput{}, nil
+	}
+	if dispatch.Kind != yaml.MappingNode {
+		return nil, &WorkflowParseError{Line: dispatch.Line, Msg: "workflow_dispatch must be a mapping"}
 	}
 
-	inputs := r.On.WorkflowDispatch.Inputs
+	inputs := yamlMapValue(dispatch, "inputs")
 
 	// this is blank inputs case
-	if len(inputs) == 0 {
+	if inputs == nil || isYamlNull(inputs) {
 		return []GhWorkflowInput{}, nil
 	}
+	if inputs.Kind != yaml.MappingNode {
+		return nil, &WorkflowParseError{Line: inputs.Line, Msg: "inputs must be a mapping"}
+	}
 
-	for _, v := range inputs {
-		name := v.Key.(string)
-
-		var (
-			required     bool
-			description  string
-			defaultValue string
-			typeValue    string = GhWorkflowInputTypeString
-			options      []string
-		)
-
-		if p, ok := v.Value.(yaml.MapSlice); ok {
-			for _, vv := range p {
-				switch vv.Key.(string) {
-				case "required":
-					required = vv.Value.(bool)
-				case "description":
-					description = vv.Value.(string)
-				case "default":
-					defaultValue, ok = vv.Value.(string)
-					if !ok {
-						defaultValue = strconv.FormatBool(vv.Value.(bool))
-					}
-				case "type":
-					typeValue = vv.Value.(string)
-				case "options":
-					for _, o := range vv.Value.([]any) {
-						options = append(options, o.(string))
-					}
+	w := []GhWorkflowInput{}
+	for i := 0; i+1 < len(inputs.Content); i += 2 {
+		input, err := parseWorkflowInput(inputs.Content[i], resolveYamlAlias(inputs.Content[i+1]))
+		if err != nil {
+			return nil, err
+		}
+		w = append(w, input)
+	}
+
+	return w, nil
+}
+
+// parseWorkflowInput parses an input of workflow_dispatch.
+func parseWorkflowInput(key, value *yaml.Node) (GhWorkflowInput, error) {
+	input := GhWorkflowInput{
+		Name: key.Value,
+		Type: GhWorkflowInputTypeString,
+	}
+
+	if isYamlNull(value) {
+		return input, nil
+	}
+	if value.Kind != yaml.MappingNode {
+		return input, &WorkflowParseError{Line: value.Line, Msg: fmt.Sprintf("input %q must be a mapping", input.Name)}
+	}
+
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		prop := value.Content[i].Value
+		v := resolveYamlAlias(value.Content[i+1])
+
+		var err error
+		switch prop {
+		case "required":
+			var s string
+			if s, err = yamlScalar(v, input.Name, prop); err == nil && s != "" {
+				if input.Required, err = strconv.ParseBool(s); err != nil {
+					err = &WorkflowParseError{Line: v.Line, Msg: fmt.Sprintf("required of input %q must be true or false, got %q", input.Name, s)}
 				}
 			}
+		case "description":
+			input.Description, err = yamlScalar(v, input.Name, prop)
+		case "default":
+			input.Default, err = yamlScalar(v, input.Name, prop)
+		case "type":
+			var s string
+			if s, err = yamlScalar(v, input.Name, prop); err == nil && s != "" {
+				input.Type = s
+			}
+		case "options":
+			input.Options, err = yamlScalars(v, input.Name, prop)
 		}
 
-		w = append(w, GhWorkflowInput{
-			Name:        name,
-			Required:    required,
-			Description: description,
-			Default:     defaultValue,
-			Type:        typeValue,
-			Options:     options,
-		})
+		if err != nil {
+			return input, err
+		}
 	}
 
-	return w, nil
+	return input, nil
 }
 
-// Run runs a workflow.
-func (w *GhWorkflow) Run(branch string, fieldArgs []struct{ Key, Value string }) error {
-	args := []string{"workflow", "run", string(w.Id), "-r", branch}
-	for _, m := range fieldArgs {
-		args = append(args, "-f", m.Key+"="+m.Value)
+// resolveYamlAlias returns the node an alias points to.
+func resolveYamlAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
 	}
-	cmd := exec.Command("gh", args...)
-	return cmd.Run()
+	return n
+}
+
+// isYamlNull reports whether n is an empty value.
+func isYamlNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+}
+
+// yamlMapValue returns the value of key in the mapping m, or nil.
+func yamlMapValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return resolveYamlAlias(m.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// yamlScalar returns the scalar n as a string, whatever its type.
+// A null value is returned as an empty string.
+func yamlScalar(n *yaml.Node, input, prop string) (string, error) {
+	if isYamlNull(n) {
+		return "", nil
+	}
+	if n.Kind != yaml.ScalarNode {
+		return "", &WorkflowParseError{Line: n.Line, Msg: fmt.Sprintf("%s of input %q must be a scalar", prop, input)}
+	}
+	return n.Value, nil
+}
+
+// yamlScalars returns the sequence of scalars n as strings.
+func yamlScalars(n *yaml.Node, input, prop string) ([]string, error) {
+	if isYamlNull(n) {
+		return nil, nil
+	}
+	if n.Kind != yaml.SequenceNode {
+		return nil, &WorkflowParseError{Line: n.Line, Msg: fmt.Sprintf("%s of input %q must be a list", prop, input)}
+	}
+
+	values := []string{}
+	for _, c := range n.Content {
+		v, err := yamlScalar(resolveYamlAlias(c), input, prop)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
 }
 
 type GhApiGetEnvironmentsResult struct {
@@ -191,60 +351,129 @@ type GhApiGetEnvironmentsResultEnvironment struct {
 	Name string `json:"name"`
 }
 
-// GetEnvironments returns current repository GitHub Environments.
-func GetEnvironments() ([]string, error) {
-	repoWithOwner, err := getCurrentRepositoryWithOwner()
+type GhRepoViewForNameWithOwnerResult struct {
+	Name string `json:"nameWithOwner"`
+	Url  string `json:"url"`
+}
+
+// localRepo is the repository of the current directory once it is found.
+var localRepo struct {
+	mu   sync.Mutex
+	repo Repo
+}
+
+// GetRepositoryWithOwner returns repository name with owner of repo
+// e.g. "t4kamura/gh-wrun"
+func GetRepositoryWithOwner(ctx context.Context, repo Repo) (string, error) {
+	r, err := ResolveRepo(ctx, repo)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	endpoint := fmt.Sprintf("/repos/%s/environments", repoWithOwner)
+	return r.Owner + "/" + r.Name, nil
+}
 
-	cmd := exec.Command("gh", "api", "-H", "Accept: application/vnd.github+json", "-H", "X-GitHub-Api-Version: 2022-11-28", endpoint)
-	out, err := cmd.Output()
-	if err != nil {
-		if len(out) > 0 {
-			var failedRes GhApiGetEnvironmentsFailedResult
-			if err := json.Unmarshal(out, &failedRes); err != nil {
-				return nil, err
-			}
-			return nil, fmt.Errorf("failed to get environments, message: %s, status: %s, doc url: %s", failedRes.Message, failedRes.Status, failedRes.DocumentationURL)
+// ResolveRepo returns repo, or the repository of the current directory if repo is local,
+// with its owner, name and host. The host is empty for github.com.
+func ResolveRepo(ctx context.Context, repo Repo) (Repo, error) {
+	if !repo.IsLocal() {
+		return repo, nil
+	}
+
+	localRepo.mu.Lock()
+	defer localRepo.mu.Unlock()
+
+	// failures are not remembered, they may come from a canceled context
+	if localRepo.repo.IsLocal() {
+		r, err := getLocalRepo(ctx)
+		if err != nil {
+			return Repo{}, err
 		}
-		return nil, err
+		localRepo.repo = r
 	}
+	return localRepo.repo, nil
+}
 
-	var environmentsResult GhApiGetEnvironmentsResult
-	if err := json.Unmarshal(out, &environmentsResult); err != nil {
-		return nil, err
+// getLocalRepo returns the repository of the current directory.
+func getLocalRepo(ctx context.Context) (Repo, error) {
+	out, err := output(ctx, "gh", "repo", "view", "--json", "nameWithOwner,url")
+	if err != nil {
+		return Repo{}, err
 	}
 
-	environments := make([]string, 0, environmentsResult.TotalCount)
-	for _, e := range environmentsResult.Environments {
-		environments = append(environments, e.Name)
+	var res GhRepoViewForNameWithOwnerResult
+	if err := json.Unmarshal(out, &res); err != nil {
+		return Repo{}, err
 	}
-	return environments, nil
+
+	return parseRepoView(res)
 }
 
-type GhRepoViewForNameWithOwnerResult struct {
-	Name string `json:"nameWithOwner"`
+// parseRepoView returns the repository described by the result of gh repo view.
+func parseRepoView(res GhRepoViewForNameWithOwnerResult) (Repo, error) {
+	owner, name, ok := strings.Cut(res.Name, "/")
+	if !ok || owner == "" || name == "" {
+		return Repo{}, fmt.Errorf("not found repository")
+	}
+
+	u, err := url.Parse(res.Url)
+	if err != nil {
+		return Repo{}, err
+	}
+
+	repo := Repo{Owner: owner, Name: name}
+	if host := u.Hostname(); host != "github.com" {
+		repo.Host = host
+	}
+	return repo, nil
 }
 
-// getCurrentRepositoryWithOwner returns current repository name with owner
-// e.g. "t4kamura/gh-wrun"
-func getCurrentRepositoryWithOwner() (string, error) {
-	cmd := exec.Command("gh", "repo", "view", "--json", "nameWithOwner")
-	out, err := cmd.Output()
+// GetDefaultBranch returns the default branch name of repo.
+func GetDefaultBranch(ctx context.Context, repo Repo) (string, error) {
+	defer progress.Start("Loading the default branch").Stop()
+
+	out, err := output(ctx, "gh", "repo", "view", repo.String(), "--json", "defaultBranchRef", "--jq", ".defaultBranchRef.name")
 	if err != nil {
 		return "", err
 	}
 
-	var res GhRepoViewForNameWithOwnerResult
-	if err := json.Unmarshal(out, &res); err != nil {
-		return "", err
+	branch := strings.TrimSpace(string(out))
+	if branch == "" {
+		return "", errors.New("Default branch not found")
 	}
+	return branch, nil
+}
+
+// GetRepoBranches returns the branch names of repo from the GitHub API.
+func GetRepoBranches(ctx context.Context, repo Repo) ([]string, error) {
+	defer progress.Start("Loading branches").Stop()
+	return getRepoRefNames(ctx, repo, "branches")
+}
+
+// GetRepoTags returns the tag names of repo from the GitHub API.
+func GetRepoTags(ctx context.Context, repo Repo) ([]string, error) {
+	defer progress.Start("Loading tags").Stop()
+	return getRepoRefNames(ctx, repo, "tags")
+}
 
-	if res.Name == "" {
-		return "", fmt.Errorf("not found repository")
+// getRepoRefNames returns the names listed by the branches or tags endpoint of repo.
+func getRepoRefNames(ctx context.Context, repo Repo, kind string) ([]string, error) {
+	repoWithOwner, err := GetRepositoryWithOwner(ctx, repo)
+	if err != nil {
+		return nil, err
 	}
+	endpoint := fmt.Sprintf("/repos/%s/%s", repoWithOwner, kind)
 
-	return res.Name, err
+	out, err := repo.ghApi(ctx, "--paginate", "--jq", ".[].name", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		if n := strings.TrimSpace(sc.Text()); n != "" {
+			names = append(names, n)
+		}
+	}
+	return names, nil
 }
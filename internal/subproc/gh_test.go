@@ -1,6 +1,7 @@
 package subproc
 
 import (
+	"errors"
 	"os"
 	"path"
 	"reflect"
@@ -16,6 +17,7 @@ func TestParseWorkflowInputs(t *testing.T) {
 		inputFileName string
 		want          []GhWorkflowInput
 		expectErr     bool
+		wantErrLine   int
 	}{
 		{
 			name:          "parse valid types",
@@ -74,6 +76,41 @@ func TestParseWorkflowInputs(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name:          "parse number type",
+			inputFileName: "valid-number.yml",
+			want: []GhWorkflowInput{
+				{
+					Name:        "number-int",
+					Description: "number int test",
+					Default:     "3",
+					Type:        GhWorkflowInputTypeNumber,
+					Required:    true,
+				},
+				{
+					Name:        "number-float",
+					Description: "number float test",
+					Default:     "1.5",
+					Type:        GhWorkflowInputTypeNumber,
+					Required:    false,
+				},
+				{
+					Name:        "number-string-default",
+					Description: "number string default test",
+					Default:     "42",
+					Type:        GhWorkflowInputTypeNumber,
+					Required:    false,
+				},
+				{
+					Name:        "number-no-default",
+					Description: "number no default test",
+					Default:     "",
+					Type:        GhWorkflowInputTypeNumber,
+					Required:    true,
+				},
+			},
+			expectErr: false,
+		},
 		// want blank inputs
 		{
 			name:          "no inputs property",
@@ -87,11 +124,70 @@ func TestParseWorkflowInputs(t *testing.T) {
 			want:          []GhWorkflowInput{},
 			expectErr:     false,
 		},
+		{
+			name:          "on as string",
+			inputFileName: "valid-on-string.yml",
+			want:          []GhWorkflowInput{},
+			expectErr:     false,
+		},
+		{
+			name:          "on as list",
+			inputFileName: "valid-on-list.yml",
+			want:          []GhWorkflowInput{},
+			expectErr:     false,
+		},
+		{
+			name:          "coerce scalar types",
+			inputFileName: "valid-coerce-types.yml",
+			want: []GhWorkflowInput{
+				{
+					Name:        "required-string",
+					Description: "required as string test",
+					Type:        GhWorkflowInputTypeString,
+					Required:    true,
+				},
+				{
+					Name:        "choice-numbers",
+					Description: "42",
+					Default:     "2",
+					Type:        GhWorkflowInputTypeChoice,
+					Required:    false,
+					Options:     []string{"1", "2", "3.5"},
+				},
+				{
+					Name: "no-properties",
+					Type: GhWorkflowInputTypeString,
+				},
+			},
+			expectErr: false,
+		},
 		{
 			name:          "invalid format",
 			inputFileName: "invalid-format.yml",
 			want:          nil,
 			expectErr:     true,
+			wantErrLine:   1,
+		},
+		{
+			name:          "invalid required",
+			inputFileName: "invalid-required.yml",
+			want:          nil,
+			expectErr:     true,
+			wantErrLine:   7,
+		},
+		{
+			name:          "invalid options",
+			inputFileName: "invalid-options.yml",
+			want:          nil,
+			expectErr:     true,
+			wantErrLine:   7,
+		},
+		{
+			name:          "invalid syntax",
+			inputFileName: "invalid-syntax.yml",
+			want:          nil,
+			expectErr:     true,
+			wantErrLine:   6,
 		},
 	}
 
@@ -110,6 +206,15 @@ func TestParseWorkflowInputs(t *testing.T) {
 				t.Errorf("Error parsing workflows: %s\n", err)
 			}
 
+			var parseErr *WorkflowParseError
+			if test.expectErr && err != nil {
+				if !errors.As(err, &parseErr) {
+					t.Errorf("Expected WorkflowParseError but got %T\n", err)
+				} else if parseErr.Line != test.wantErrLine {
+					t.Errorf("Expected error at line %d but got %d: %s\n", test.wantErrLine, parseErr.Line, err)
+				}
+			}
+
 			if !test.expectErr && len(got) != len(test.want) {
 				t.Errorf("The number of elements is different. Expected is %d but got %d\n", len(test.want), len(got))
 			}
@@ -120,3 +225,88 @@ func TestParseWorkflowInputs(t *testing.T) {
 		})
 	}
 }
+
+func TestFindWorkflow(t *testing.T) {
+	workflows := []GhWorkflow{
+		{Id: "1", Name: "Build", Path: ".github/workflows/build.yml", Status: "active"},
+		{Id: "2", Name: "Deploy", Path: ".github/workflows/deploy.yml", Status: "active"},
+	}
+
+	testCases := []struct {
+		name      string
+		key       string
+		wantId    string
+		expectErr bool
+	}{
+		{name: "by id", key: "2", wantId: "2"},
+		{name: "by name", key: "Build", wantId: "1"},
+		{name: "by path", key: ".github/workflows/deploy.yml", wantId: "2"},
+		{name: "by file name", key: "deploy.yml", wantId: "2"},
+		{name: "not found", key: "release.yml", expectErr: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := FindWorkflow(workflows, test.key)
+
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("Expected error but got nil\n")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err)
+			}
+
+			if string(got.Id) != test.wantId {
+				t.Errorf("Expected is %s but got %s\n", test.wantId, got.Id)
+			}
+		})
+	}
+}
+
+func TestParseWorkflowEvents(t *testing.T) {
+	testCases := []struct {
+		name             string
+		inputFileName    string
+		want             []string
+		wantDispatchable bool
+		expectErr        bool
+	}{
+		{name: "on string", inputFileName: "valid-on-string.yml", want: []string{"workflow_dispatch"}, wantDispatchable: true},
+		{name: "on list", inputFileName: "valid-on-list.yml", want: []string{"push", "workflow_dispatch"}, wantDispatchable: true},
+		{name: "on mapping", inputFileName: "valid-all-types.yml", want: []string{"workflow_dispatch"}, wantDispatchable: true},
+		{name: "no dispatch", inputFileName: "valid-no-dispatch.yml", want: []string{"push", "schedule"}, wantDispatchable: false},
+		{name: "invalid syntax", inputFileName: "invalid-syntax.yml", expectErr: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			file, err := os.ReadFile(path.Join(testDataDir, test.inputFileName))
+			if err != nil {
+				t.Fatalf("Error reading file: %s\n", err)
+			}
+
+			got, err := parseWorkflowEvents(file)
+
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("Expected error but got nil\n")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Error parsing workflow events: %s\n", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Expected is %v but got %v\n", test.want, got)
+			}
+
+			if IsDispatchable(got) != test.wantDispatchable {
+				t.Errorf("Expected dispatchable is %v but got %v\n", test.wantDispatchable, IsDispatchable(got))
+			}
+		})
+	}
+}
@@ -3,35 +3,304 @@ package subproc
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+
+	"github.com/t4kamura/gh-wrun/internal/progress"
 )
 
+// RemoteBranch is a branch of a git remote.
+type RemoteBranch struct {
+	Remote, Name string
+}
+
 // getBranchName returns the current branch name.
-func GetBranchName() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	out, err := cmd.Output()
+func GetBranchName(ctx context.Context) (string, error) {
+	out, err := output(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GetExactTag returns the tag pointing exactly at HEAD.
+// It returns an empty string if there is none.
+func GetExactTag(ctx context.Context) (string, error) {
+	out, err := output(ctx, "git", "describe", "--tags", "--exact-match", "HEAD")
 	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// git describe fails when no tag matches
+			return "", nil
+		}
 		return "", err
 	}
 	return strings.TrimSpace(string(out)), nil
 }
 
+// BranchStatus is the state of the current branch compared to its upstream.
+type BranchStatus struct {
+	// Upstream is the remote tracking ref, e.g. "origin/main", empty if not set.
+	Upstream string
+	// Ahead is the number of local commits not pushed to the upstream.
+	Ahead int
+	// Behind is the number of upstream commits not in the local branch.
+	Behind int
+	// Dirty reports uncommitted changes in the working tree.
+	Dirty bool
+}
+
+// GetBranchStatus returns the state of the current branch.
+func GetBranchStatus(ctx context.Context) (BranchStatus, error) {
+	var status BranchStatus
+
+	out, err := output(ctx, "git", "status", "--porcelain")
+	if err != nil {
+		return status, err
+	}
+	status.Dirty = len(bytes.TrimSpace(out)) > 0
+
+	out, err = output(ctx, "git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	if err != nil {
+		// no upstream is configured
+		return status, nil
+	}
+	status.Upstream = strings.TrimSpace(string(out))
+
+	out, err = output(ctx, "git", "rev-list", "--left-right", "--count", "HEAD...@{u}")
+	if err != nil {
+		return status, err
+	}
+
+	status.Ahead, status.Behind, err = parseAheadBehind(&out)
+	return status, err
+}
+
+// parseAheadBehind parses the output of `git rev-list --left-right --count`.
+func parseAheadBehind(out *[]byte) (int, int, error) {
+	fields := strings.Fields(string(*out))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("Error parsing ahead/behind counts: %q", string(*out))
+	}
+
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// Push pushes the current branch.
+// If remote is not empty, the branch is pushed to it and set as upstream.
+func Push(ctx context.Context, remote, branch string) error {
+	args := []string{"push"}
+	if remote != "" {
+		args = append(args, "-u", remote, branch)
+	}
+
+	// pushing may take long, it is only stopped by ctx
+	cmd := interruptibleCommand(ctx, "git", args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// GetRemotes returns the names of the git remotes.
+func GetRemotes(ctx context.Context) ([]string, error) {
+	out, err := output(ctx, "git", "remote")
+	if err != nil {
+		return []string{}, err
+	}
+
+	return strings.Fields(string(out)), nil
+}
+
+// FindRemote returns the remote pointing to the repository repoWithOwner,
+// e.g. "t4kamura/gh-wrun". It returns an empty string if none matches.
+func FindRemote(ctx context.Context, repoWithOwner string) (string, error) {
+	remotes, err := GetRemotes(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, r := range remotes {
+		out, err := output(ctx, "git", "remote", "get-url", r)
+		if err != nil {
+			return "", err
+		}
+
+		if strings.EqualFold(repoFromRemoteURL(strings.TrimSpace(string(out))), repoWithOwner) {
+			return r, nil
+		}
+	}
+
+	return "", nil
+}
+
+// GetRemoteRefSha returns the SHA the branch or tag ref points to on remote,
+// asking the remote itself since the remote-tracking branches may be outdated.
+// If ref is empty, the default branch of remote is used.
+// It returns an empty string if remote has no such ref.
+func GetRemoteRefSha(ctx context.Context, remote, ref string) (string, error) {
+	patterns := []string{"HEAD"}
+	if ref != "" {
+		patterns = []string{"refs/heads/" + ref, "refs/tags/" + ref}
+	}
+
+	out, err := output(ctx, "git", append([]string{"ls-remote", remote}, patterns...)...)
+	if err != nil {
+		return "", err
+	}
+	return parseLsRemoteSha(&out, patterns), nil
+}
+
+// parseLsRemoteSha parses the output of `git ls-remote` and returns the SHA
+// of the first of refs listed, or an empty string if none is.
+func parseLsRemoteSha(out *[]byte, refs []string) string {
+	shas := map[string]string{}
+	sc := bufio.NewScanner(bytes.NewReader(*out))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 2 {
+			shas[fields[1]] = fields[0]
+		}
+	}
+
+	for _, r := range refs {
+		if sha, ok := shas[r]; ok {
+			return sha
+		}
+	}
+	return ""
+}
+
+// repoFromRemoteURL returns the owner/repo part of a git remote URL.
+// Both URL (https://, ssh://) and scp-like (git@host:owner/repo) forms are supported.
+func repoFromRemoteURL(url string) string {
+	path := url
+	if i := strings.Index(path, "://"); i >= 0 {
+		// drop scheme and host
+		path = path[i+3:]
+		if j := strings.Index(path, "/"); j >= 0 {
+			path = path[j+1:]
+		}
+	} else if i := strings.Index(path, ":"); i >= 0 {
+		path = path[i+1:]
+	}
+
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[len(parts)-2] + "/" + parts[len(parts)-1]
+}
+
 // getRemoteBranches returns the list of remote branches.
-func GetRemoteBranches() ([]string, error) {
-	cmd := exec.Command("git", "branch", "-r")
-	out, err := cmd.Output()
+// If remote is not empty, only its branches are returned,
+// otherwise the branches of every remote are merged without duplicates.
+func GetRemoteBranches(ctx context.Context, remote string) ([]string, error) {
+	remotes, err := GetRemotes(ctx)
 	if err != nil {
 		return []string{}, err
 	}
 
-	branches := parseBranchesResult(&out)
+	out, err := output(ctx, "git", "branch", "-r")
+	if err != nil {
+		return []string{}, err
+	}
 
-	return branches, nil
+	return filterBranches(parseBranchesResult(&out, remotes), remote), nil
+}
+
+// GetRemoteTags returns the tags of remote, or of all remotes
+// if remote is empty, without duplicates.
+// On error, the tags of the remotes listed so far are returned.
+func GetRemoteTags(ctx context.Context, remote string) ([]string, error) {
+	remotes := []string{remote}
+	if remote == "" {
+		var err error
+		if remotes, err = GetRemotes(ctx); err != nil {
+			return []string{}, err
+		}
+	}
+
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, r := range remotes {
+		rTags, err := getRemoteTags(ctx, r)
+		if err != nil {
+			return tags, err
+		}
+		for _, t := range rTags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	return tags, nil
+}
+
+// getRemoteTags returns the tags of remote.
+func getRemoteTags(ctx context.Context, remote string) ([]string, error) {
+	defer progress.Start("Loading tags of " + remote).Stop()
+
+	out, err := output(ctx, "git", "ls-remote", "--tags", "--refs", remote)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return parseLsRemoteTags(&out), nil
+}
+
+// parseLsRemoteTags parses the output of `git ls-remote --tags` and returns the tag names.
+func parseLsRemoteTags(out *[]byte) []string {
+	sc := bufio.NewScanner(bytes.NewReader(*out))
+	tags := []string{}
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		tag, ok := strings.CutPrefix(fields[1], "refs/tags/")
+		// peeled tags are listed as v1.0.0^{} without --refs
+		if ok && !strings.HasSuffix(tag, "^{}") {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// filterBranches returns the names of the branches of remote, or of all remotes
+// if remote is empty, without duplicates.
+func filterBranches(branches []RemoteBranch, remote string) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, b := range branches {
+		if (remote != "" && b.Remote != remote) || seen[b.Name] {
+			continue
+		}
+		seen[b.Name] = true
+		names = append(names, b.Name)
+	}
+	return names
 }
 
 // parseBranchesResult parses the output of `git branch -r` and returns the list of branches.
-func parseBranchesResult(out *[]byte) []string {
+// The remote part is matched against remotes, so branch names may contain slashes.
+func parseBranchesResult(out *[]byte, remotes []string) []RemoteBranch {
 	sc := bufio.NewScanner(bytes.NewReader(*out))
 	branches := []string{}
 	for sc.Scan() {
@@ -50,10 +319,20 @@ func parseBranchesResult(out *[]byte) []string {
 		}
 	}
 
-	// Remove the "origin/" prefix
-	formattedBranches := []string{}
+	// Split into the remote and the branch name,
+	// preferring the longest remote in case a remote name contains a slash
+	formattedBranches := []RemoteBranch{}
 	for _, b := range branches {
-		formattedBranches = append(formattedBranches, strings.Split(b, "/")[1])
+		var found RemoteBranch
+		for _, r := range remotes {
+			if strings.HasPrefix(b, r+"/") && len(r) > len(found.Remote) {
+				found = RemoteBranch{Remote: r, Name: strings.TrimPrefix(b, r+"/")}
+			}
+		}
+
+		if found.Remote != "" {
+			formattedBranches = append(formattedBranches, found)
+		}
 	}
 
 	return formattedBranches
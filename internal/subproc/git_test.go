@@ -9,61 +9,243 @@ func TestParseBranchesResult(t *testing.T) {
 	tests := []struct {
 		name string
 		out  []byte
-		want []string
+		want []RemoteBranch
 	}{
 		{
 			name: "empty",
 			out:  []byte{},
-			want: []string{},
+			want: []RemoteBranch{},
 		},
 		{
 			name: "one branch",
 			out:  []byte("  origin/main\n"),
-			want: []string{"main"},
+			want: []RemoteBranch{{Remote: "origin", Name: "main"}},
 		},
 		{
 			name: "two branches",
 			out:  []byte("  origin/main\n  origin/feature\n"),
-			want: []string{"main", "feature"},
+			want: []RemoteBranch{{Remote: "origin", Name: "main"}, {Remote: "origin", Name: "feature"}},
 		},
 		{
 			name: "two branches with HEAD",
 			out:  []byte("  origin/HEAD -> origin/main\n  origin/main\n"),
-			want: []string{"main"},
+			want: []RemoteBranch{{Remote: "origin", Name: "main"}},
 		},
 		{
 			name: "two branches with HEAD and spaces",
 			out:  []byte("  origin/HEAD -> origin/main\n  origin/main\n  origin/feature\n"),
-			want: []string{"main", "feature"},
+			want: []RemoteBranch{{Remote: "origin", Name: "main"}, {Remote: "origin", Name: "feature"}},
 		},
 		{
 			name: "two branches with HEAD and spaces and tabs",
 			out:  []byte("  origin/HEAD -> origin/main\n  origin/main\n  origin/feature\n\torigin/feature2\n"),
-			want: []string{"main", "feature", "feature2"},
+			want: []RemoteBranch{{Remote: "origin", Name: "main"}, {Remote: "origin", Name: "feature"}, {Remote: "origin", Name: "feature2"}},
 		},
 		{
 			name: "two branches with HEAD and spaces and tabs and newline",
 			out:  []byte("  origin/HEAD -> origin/main\n  origin/main\n  origin/feature\n\torigin/feature2\n\n"),
-			want: []string{"main", "feature", "feature2"},
+			want: []RemoteBranch{{Remote: "origin", Name: "main"}, {Remote: "origin", Name: "feature"}, {Remote: "origin", Name: "feature2"}},
 		},
 		{
 			name: "two branches with HEAD and spaces and tabs and newline and spaces",
 			out:  []byte("  origin/HEAD -> origin/main\n  origin/main\n  origin/feature\n\torigin/feature2\n\n  origin/feature3\n"),
-			want: []string{"main", "feature", "feature2", "feature3"},
+			want: []RemoteBranch{{Remote: "origin", Name: "main"}, {Remote: "origin", Name: "feature"}, {Remote: "origin", Name: "feature2"}, {Remote: "origin", Name: "feature3"}},
 		},
 		{
 			name: "two branches with HEAD and spaces and tabs and newline and spaces and spaces",
 			out:  []byte("  origin/HEAD -> origin/main\n  origin/main\n  origin/feature\n\torigin/feature2\n\n  origin/feature3\n  origin/feature4\n"),
-			want: []string{"main", "feature", "feature2", "feature3", "feature4"},
+			want: []RemoteBranch{{Remote: "origin", Name: "main"}, {Remote: "origin", Name: "feature"}, {Remote: "origin", Name: "feature2"}, {Remote: "origin", Name: "feature3"}, {Remote: "origin", Name: "feature4"}},
+		},
+		{
+			name: "branch names with slashes",
+			out:  []byte("  origin/HEAD -> origin/main\n  origin/main\n  origin/feature/login\n  origin/release/v1/hotfix\n"),
+			want: []RemoteBranch{{Remote: "origin", Name: "main"}, {Remote: "origin", Name: "feature/login"}, {Remote: "origin", Name: "release/v1/hotfix"}},
+		},
+		{
+			name: "multiple remotes",
+			out:  []byte("  origin/main\n  upstream/HEAD -> upstream/main\n  upstream/main\n  upstream/feature/login\n"),
+			want: []RemoteBranch{{Remote: "origin", Name: "main"}, {Remote: "upstream", Name: "main"}, {Remote: "upstream", Name: "feature/login"}},
+		},
+		{
+			name: "remote name with slash",
+			out:  []byte("  team/fork/main\n  unknown/main\n"),
+			want: []RemoteBranch{{Remote: "team/fork", Name: "main"}},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := parseBranchesResult(&tt.out)
+			got := parseBranchesResult(&tt.out, []string{"origin", "upstream", "team/fork"})
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("parseBranchesResult() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestFilterBranches(t *testing.T) {
+	branches := []RemoteBranch{
+		{Remote: "origin", Name: "main"},
+		{Remote: "origin", Name: "feature/login"},
+		{Remote: "upstream", Name: "main"},
+		{Remote: "upstream", Name: "release"},
+	}
+
+	tests := []struct {
+		name   string
+		remote string
+		want   []string
+	}{
+		{name: "origin", remote: "origin", want: []string{"main", "feature/login"}},
+		{name: "upstream", remote: "upstream", want: []string{"main", "release"}},
+		{name: "all remotes without duplicates", remote: "", want: []string{"main", "feature/login", "release"}},
+		{name: "unknown remote", remote: "unknown", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterBranches(branches, tt.remote)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterBranches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoFromRemoteURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "https", url: "https://github.com/t4kamura/gh-wrun.git", want: "t4kamura/gh-wrun"},
+		{name: "https without .git", url: "https://github.com/t4kamura/gh-wrun", want: "t4kamura/gh-wrun"},
+		{name: "scp-like ssh", url: "git@github.com:t4kamura/gh-wrun.git", want: "t4kamura/gh-wrun"},
+		{name: "ssh", url: "ssh://git@github.example.com:2222/t4kamura/gh-wrun.git", want: "t4kamura/gh-wrun"},
+		{name: "no owner", url: "gh-wrun", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := repoFromRemoteURL(tt.url)
+			if got != tt.want {
+				t.Errorf("repoFromRemoteURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLsRemoteTags(t *testing.T) {
+	tests := []struct {
+		name string
+		out  []byte
+		want []string
+	}{
+		{
+			name: "empty",
+			out:  []byte{},
+			want: []string{},
+		},
+		{
+			name: "tags",
+			out:  []byte("0123abcd\trefs/tags/v1.4.1\n4567cdef\trefs/tags/release/v1.4.2\n"),
+			want: []string{"v1.4.1", "release/v1.4.2"},
+		},
+		{
+			name: "peeled tags",
+			out:  []byte("0123abcd\trefs/tags/v1.4.1\n4567cdef\trefs/tags/v1.4.1^{}\n"),
+			want: []string{"v1.4.1"},
+		},
+		{
+			name: "not a tag",
+			out:  []byte("0123abcd\trefs/heads/main\nwarning: redirecting\n"),
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseLsRemoteTags(&tt.out)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLsRemoteTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLsRemoteSha(t *testing.T) {
+	tests := []struct {
+		name string
+		out  []byte
+		refs []string
+		want string
+	}{
+		{
+			name: "not found",
+			out:  []byte{},
+			refs: []string{"refs/heads/feature", "refs/tags/feature"},
+			want: "",
+		},
+		{
+			name: "branch before tag",
+			out:  []byte("4567cdef\trefs/tags/feature\n0123abcd\trefs/heads/feature\n"),
+			refs: []string{"refs/heads/feature", "refs/tags/feature"},
+			want: "0123abcd",
+		},
+		{
+			name: "tag",
+			out:  []byte("4567cdef\trefs/tags/v1.0.0\n"),
+			refs: []string{"refs/heads/v1.0.0", "refs/tags/v1.0.0"},
+			want: "4567cdef",
+		},
+		{
+			name: "default branch",
+			out:  []byte("0123abcd\tHEAD\n"),
+			refs: []string{"HEAD"},
+			want: "0123abcd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseLsRemoteSha(&tt.out, tt.refs)
+			if got != tt.want {
+				t.Errorf("parseLsRemoteSha() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAheadBehind(t *testing.T) {
+	tests := []struct {
+		name       string
+		out        []byte
+		wantAhead  int
+		wantBehind int
+		expectErr  bool
+	}{
+		{name: "up to date", out: []byte("0\t0\n"), wantAhead: 0, wantBehind: 0},
+		{name: "ahead", out: []byte("3\t0\n"), wantAhead: 3, wantBehind: 0},
+		{name: "diverged", out: []byte("2\t5\n"), wantAhead: 2, wantBehind: 5},
+		{name: "empty", out: []byte(""), expectErr: true},
+		{name: "not a number", out: []byte("a\tb\n"), expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ahead, behind, err := parseAheadBehind(&tt.out)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("parseAheadBehind() expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAheadBehind() unexpected error: %s", err)
+			}
+			if ahead != tt.wantAhead || behind != tt.wantBehind {
+				t.Errorf("parseAheadBehind() = %d, %d, want %d, %d", ahead, behind, tt.wantAhead, tt.wantBehind)
+			}
+		})
+	}
+}
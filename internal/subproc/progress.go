@@ -0,0 +1,47 @@
+package subproc
+
+import (
+	"context"
+
+	"github.com/t4kamura/gh-wrun/internal/progress"
+)
+
+// ProgressClient is a Client showing a progress indicator while its calls run.
+type ProgressClient struct {
+	Client
+}
+
+// NewProgressClient returns a Client showing the progress of the calls to c.
+func NewProgressClient(c Client) *ProgressClient {
+	return &ProgressClient{Client: c}
+}
+
+// Unwrap returns the Client without progress, e.g. for background calls.
+func (c *ProgressClient) Unwrap() Client {
+	return c.Client
+}
+
+func (c *ProgressClient) ListWorkflows(ctx context.Context, all bool) ([]GhWorkflow, error) {
+	defer progress.Start("Loading workflows").Stop()
+	return c.Client.ListWorkflows(ctx, all)
+}
+
+func (c *ProgressClient) EnableWorkflow(ctx context.Context, w GhWorkflow) error {
+	defer progress.Start("Enabling " + w.Name).Stop()
+	return c.Client.EnableWorkflow(ctx, w)
+}
+
+func (c *ProgressClient) GetWorkflowFile(ctx context.Context, w GhWorkflow, ref string) ([]byte, error) {
+	defer progress.Start("Loading " + w.Path).Stop()
+	return c.Client.GetWorkflowFile(ctx, w, ref)
+}
+
+func (c *ProgressClient) ListEnvironments(ctx context.Context) ([]string, error) {
+	defer progress.Start("Loading environments").Stop()
+	return c.Client.ListEnvironments(ctx)
+}
+
+func (c *ProgressClient) Dispatch(ctx context.Context, w GhWorkflow, ref string, inputs []struct{ Key, Value string }) error {
+	defer progress.Start("Dispatching " + w.Name).Stop()
+	return c.Client.Dispatch(ctx, w, ref, inputs)
+}
@@ -0,0 +1,96 @@
+package subproc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Repo is the repository that gh commands target.
+// The zero value targets the repository of the current directory.
+type Repo struct {
+	Host  string
+	Owner string
+	Name  string
+}
+
+// ParseRepo parses a repository in the [HOST/]OWNER/REPO format.
+// An empty string returns the zero value.
+func ParseRepo(s string) (Repo, error) {
+	if s == "" {
+		return Repo{}, nil
+	}
+
+	parts := strings.Split(s, "/")
+	for _, p := range parts {
+		if p == "" {
+			return Repo{}, fmt.Errorf("invalid repository %q, expected [HOST/]OWNER/REPO", s)
+		}
+	}
+
+	switch len(parts) {
+	case 2:
+		return Repo{Owner: parts[0], Name: parts[1]}, nil
+	case 3:
+		return Repo{Host: parts[0], Owner: parts[1], Name: parts[2]}, nil
+	default:
+		return Repo{}, fmt.Errorf("invalid repository %q, expected [HOST/]OWNER/REPO", s)
+	}
+}
+
+// IsLocal reports whether r targets the repository of the current directory.
+func (r Repo) IsLocal() bool {
+	return r == Repo{}
+}
+
+// String returns r in the [HOST/]OWNER/REPO format.
+func (r Repo) String() string {
+	if r.IsLocal() {
+		return ""
+	}
+	if r.Host != "" {
+		return r.Host + "/" + r.Owner + "/" + r.Name
+	}
+	return r.Owner + "/" + r.Name
+}
+
+// repoArgs returns the gh flag selecting r, if any.
+func (r Repo) repoArgs() []string {
+	if r.IsLocal() {
+		return nil
+	}
+	return []string{"-R", r.String()}
+}
+
+// hostArgs returns the gh api flag selecting the host of r, if any.
+func (r Repo) hostArgs() []string {
+	if r.Host == "" {
+		return nil
+	}
+	return []string{"--hostname", r.Host}
+}
+
+// gh runs a gh command targeting r, see output.
+func (r Repo) gh(ctx context.Context, args ...string) ([]byte, error) {
+	return output(ctx, "gh", append(args, r.repoArgs()...)...)
+}
+
+// ghApi runs a gh api command on the host of r, see output.
+func (r Repo) ghApi(ctx context.Context, args ...string) ([]byte, error) {
+	apiArgs, err := r.ghApiArgs(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return output(ctx, "gh", apiArgs...)
+}
+
+// ghApiArgs returns the arguments of gh api on the host of r.
+// gh api does not follow the host of the current directory,
+// so the local repository is resolved first.
+func (r Repo) ghApiArgs(ctx context.Context, args ...string) ([]string, error) {
+	resolved, err := ResolveRepo(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]string{"api"}, resolved.hostArgs()...), args...), nil
+}
@@ -0,0 +1,116 @@
+package subproc
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseRepo(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		want      Repo
+		expectErr bool
+	}{
+		{name: "empty", input: "", want: Repo{}},
+		{name: "owner and repo", input: "t4kamura/gh-wrun", want: Repo{Owner: "t4kamura", Name: "gh-wrun"}},
+		{name: "with host", input: "github.example.com/t4kamura/gh-wrun", want: Repo{Host: "github.example.com", Owner: "t4kamura", Name: "gh-wrun"}},
+		{name: "repo only", input: "gh-wrun", expectErr: true},
+		{name: "empty owner", input: "/gh-wrun", expectErr: true},
+		{name: "too many parts", input: "a/b/c/d", expectErr: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseRepo(test.input)
+
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("Expected error but got nil\n")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err)
+			}
+
+			if got != test.want {
+				t.Errorf("Expected is %v but got %v\n", test.want, got)
+			}
+
+			if got.String() != test.input {
+				t.Errorf("Expected is %s but got %s\n", test.input, got.String())
+			}
+		})
+	}
+}
+
+func TestParseRepoView(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     GhRepoViewForNameWithOwnerResult
+		want      Repo
+		expectErr bool
+	}{
+		{name: "github.com", input: GhRepoViewForNameWithOwnerResult{Name: "t4kamura/gh-wrun", Url: "https://github.com/t4kamura/gh-wrun"}, want: Repo{Owner: "t4kamura", Name: "gh-wrun"}},
+		{name: "enterprise server", input: GhRepoViewForNameWithOwnerResult{Name: "t4kamura/deploy-configs", Url: "https://github.example.com/t4kamura/deploy-configs"}, want: Repo{Host: "github.example.com", Owner: "t4kamura", Name: "deploy-configs"}},
+		{name: "no name", input: GhRepoViewForNameWithOwnerResult{}, expectErr: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseRepoView(test.input)
+
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("Expected error but got nil\n")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err)
+			}
+			if got != test.want {
+				t.Errorf("Expected is %v but got %v\n", test.want, got)
+			}
+		})
+	}
+}
+
+func TestGhApiArgs(t *testing.T) {
+	// the current directory is a checkout of an enterprise server repository
+	localRepo.mu.Lock()
+	org := localRepo.repo
+	localRepo.repo = Repo{Host: "github.example.com", Owner: "t4kamura", Name: "deploy-configs"}
+	localRepo.mu.Unlock()
+	defer func() {
+		localRepo.mu.Lock()
+		localRepo.repo = org
+		localRepo.mu.Unlock()
+	}()
+
+	testCases := []struct {
+		name string
+		repo Repo
+		want []string
+	}{
+		{name: "local", repo: Repo{}, want: []string{"api", "--hostname", "github.example.com", "user"}},
+		{name: "github.com", repo: Repo{Owner: "t4kamura", Name: "gh-wrun"}, want: []string{"api", "user"}},
+		{name: "host", repo: Repo{Host: "ghe.example.org", Owner: "t4kamura", Name: "gh-wrun"}, want: []string{"api", "--hostname", "ghe.example.org", "user"}},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.repo.ghApiArgs(context.Background(), "user")
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Expected is %v but got %v\n", test.want, got)
+			}
+		})
+	}
+}
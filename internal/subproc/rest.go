@@ -0,0 +1,282 @@
+package subproc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RestClient is a Client calling the GitHub REST API directly
+// with the token of gh, avoiding a process per call.
+type RestClient struct {
+	Repo Repo
+
+	baseURL       string
+	repoWithOwner string
+	token         string
+	http          *http.Client
+}
+
+// NewRestClient returns a Client calling the GitHub REST API of repo.
+// The token is obtained from gh auth token.
+func NewRestClient(ctx context.Context, repo Repo) (*RestClient, error) {
+	// the local repository may be on another host than github.com
+	resolved, err := ResolveRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"auth", "token"}
+	if resolved.Host != "" {
+		args = append(args, "--hostname", resolved.Host)
+	}
+	out, err := output(ctx, "gh", args...)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting gh auth token: %w", err)
+	}
+
+	return &RestClient{
+		Repo:          repo,
+		baseURL:       apiBaseURL(resolved.Host),
+		repoWithOwner: resolved.Owner + "/" + resolved.Name,
+		token:         strings.TrimSpace(string(out)),
+		// the requests are limited by their context, see callContext
+		http: &http.Client{},
+	}, nil
+}
+
+type restWorkflowsResult struct {
+	Workflows []GhWorkflow `json:"workflows"`
+}
+
+type restRunsResult struct {
+	WorkflowRuns []struct {
+		Id         json.Number `json:"id"`
+		RunNumber  int         `json:"run_number"`
+		Status     string      `json:"status"`
+		Conclusion string      `json:"conclusion"`
+		HtmlUrl    string      `json:"html_url"`
+		HeadBranch string      `json:"head_branch"`
+		CreatedAt  time.Time   `json:"created_at"`
+	} `json:"workflow_runs"`
+}
+
+func (c *RestClient) ListWorkflows(ctx context.Context, all bool) ([]GhWorkflow, error) {
+	// gh workflow list only shows active workflows
+	workflows := []GhWorkflow{}
+	err := c.getPages(ctx, "/actions/workflows?per_page=100", func(out []byte) error {
+		var res restWorkflowsResult
+		if err := json.Unmarshal(out, &res); err != nil {
+			return err
+		}
+		for _, w := range res.Workflows {
+			if all || w.IsActive() {
+				w.Repo = c.Repo
+				workflows = append(workflows, w)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(workflows) == 0 {
+		return nil, errors.New("No workflows found")
+	}
+
+	return workflows, nil
+}
+
+func (c *RestClient) EnableWorkflow(ctx context.Context, w GhWorkflow) error {
+	_, err := c.do(ctx, http.MethodPut, fmt.Sprintf("/actions/workflows/%s/enable", w.Id), "application/vnd.github+json", nil)
+	return err
+}
+
+func (c *RestClient) GetWorkflowFile(ctx context.Context, w GhWorkflow, ref string) ([]byte, error) {
+	path := "/contents/" + w.Path
+	if ref != "" {
+		path += "?ref=" + url.QueryEscape(ref)
+	}
+
+	return c.do(ctx, http.MethodGet, path, "application/vnd.github.raw", nil)
+}
+
+func (c *RestClient) ListEnvironments(ctx context.Context) ([]string, error) {
+	environments := []string{}
+	err := c.getPages(ctx, "/environments?per_page=100", func(out []byte) error {
+		var res GhApiGetEnvironmentsResult
+		if err := json.Unmarshal(out, &res); err != nil {
+			return err
+		}
+		for _, e := range res.Environments {
+			environments = append(environments, e.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return environments, nil
+}
+
+func (c *RestClient) Dispatch(ctx context.Context, w GhWorkflow, ref string, inputs []struct{ Key, Value string }) error {
+	b, err := dispatchPayload(ref, inputs)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(ctx, http.MethodPost, dispatchPath(w), "application/vnd.github+json", b)
+	return err
+}
+
+func (c *RestClient) ListDispatchRuns(ctx context.Context, w GhWorkflow, ref, actor string) ([]GhRun, error) {
+	q := url.Values{}
+	q.Set("branch", ref)
+	q.Set("event", "workflow_dispatch")
+	q.Set("per_page", "20")
+	if actor != "" {
+		q.Set("actor", actor)
+	}
+
+	var res restRunsResult
+	if err := c.getJSON(ctx, "/actions/workflows/"+string(w.Id)+"/runs?"+q.Encode(), &res); err != nil {
+		return nil, err
+	}
+
+	runs := []GhRun{}
+	for _, r := range res.WorkflowRuns {
+		runs = append(runs, GhRun{
+			Id:         r.Id,
+			Number:     r.RunNumber,
+			Status:     r.Status,
+			Conclusion: r.Conclusion,
+			Url:        r.HtmlUrl,
+			HeadBranch: r.HeadBranch,
+			CreatedAt:  r.CreatedAt,
+		})
+	}
+	return runs, nil
+}
+
+// apiBaseURL returns the REST API URL of host, github.com if empty.
+func apiBaseURL(host string) string {
+	if host != "" && host != "github.com" {
+		return "https://" + host + "/api/v3"
+	}
+	return "https://api.github.com"
+}
+
+// dispatchPath returns the repository endpoint dispatching the workflow.
+func dispatchPath(w GhWorkflow) string {
+	return "/actions/workflows/" + string(w.Id) + "/dispatches"
+}
+
+// dispatchPayload returns the JSON body of a workflow dispatch.
+func dispatchPayload(ref string, inputs []struct{ Key, Value string }) ([]byte, error) {
+	body := struct {
+		Ref    string            `json:"ref"`
+		Inputs map[string]string `json:"inputs,omitempty"`
+	}{
+		Ref:    ref,
+		Inputs: map[string]string{},
+	}
+	for _, i := range inputs {
+		body.Inputs[i.Key] = i.Value
+	}
+
+	return json.Marshal(body)
+}
+
+// getJSON gets the repository endpoint path and decodes the JSON response into v.
+func (c *RestClient) getJSON(ctx context.Context, path string, v any) error {
+	out, err := c.do(ctx, http.MethodGet, path, "application/vnd.github+json", nil)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(out, v)
+}
+
+// getPages gets every page of the repository endpoint path, following the Link headers,
+// and passes each response body to page.
+func (c *RestClient) getPages(ctx context.Context, path string, page func(out []byte) error) error {
+	u := c.baseURL + "/repos/" + c.repoWithOwner + path
+	for u != "" {
+		out, header, err := c.send(ctx, http.MethodGet, u, "application/vnd.github+json", nil)
+		if err != nil {
+			return err
+		}
+		if err := page(out); err != nil {
+			return err
+		}
+		u = nextPageURL(header.Get("Link"))
+	}
+	return nil
+}
+
+// nextPageURL returns the URL of the next page in the Link header, if any,
+// e.g. `<https://api.github.com/repositories/1/environments?page=2>; rel="next"`.
+func nextPageURL(link string) string {
+	for _, l := range strings.Split(link, ",") {
+		u, params, ok := strings.Cut(strings.TrimSpace(l), ";")
+		if !ok || !strings.HasPrefix(u, "<") || !strings.HasSuffix(u, ">") {
+			continue
+		}
+		for _, p := range strings.Split(params, ";") {
+			if strings.TrimSpace(p) == `rel="next"` {
+				return strings.TrimSuffix(strings.TrimPrefix(u, "<"), ">")
+			}
+		}
+	}
+	return ""
+}
+
+// do sends a request to the repository endpoint path and returns the response body.
+func (c *RestClient) do(ctx context.Context, method, path, accept string, body []byte) ([]byte, error) {
+	out, _, err := c.send(ctx, method, c.baseURL+"/repos/"+c.repoWithOwner+path, accept, body)
+	return out, err
+}
+
+// send sends a request to u and returns the response body and headers.
+func (c *RestClient) send(ctx context.Context, method, u, accept string, body []byte) ([]byte, http.Header, error) {
+	callCtx, cancel := callContext(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(callCtx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+			return nil, nil, &TimeoutError{Call: method + " " + u, Timeout: callTimeout(ctx)}
+		}
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	out, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res.StatusCode >= 300 {
+		return nil, nil, apiError(res.StatusCode, out)
+	}
+
+	return out, res.Header, nil
+}
@@ -0,0 +1,212 @@
+package subproc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// newTestRestClient returns a RestClient of t4kamura/gh-wrun calling a server with handler.
+func newTestRestClient(t *testing.T, handler http.Handler) *RestClient {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Expected is %s but got %s\n", "Bearer secret", got)
+		}
+		if got := r.Header.Get("X-GitHub-Api-Version"); got != "2022-11-28" {
+			t.Errorf("Expected is %s but got %s\n", "2022-11-28", got)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &RestClient{
+		Repo:          Repo{Owner: "t4kamura", Name: "gh-wrun"},
+		baseURL:       srv.URL,
+		repoWithOwner: "t4kamura/gh-wrun",
+		token:         "secret",
+		http:          srv.Client(),
+	}
+}
+
+// wantAccept fails t if the request does not accept the media type want.
+func wantAccept(t *testing.T, r *http.Request, want string) {
+	if got := r.Header.Get("Accept"); got != want {
+		t.Errorf("Expected is %s but got %s\n", want, got)
+	}
+}
+
+func TestRestClientListWorkflows(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/t4kamura/gh-wrun/actions/workflows", func(w http.ResponseWriter, r *http.Request) {
+		wantAccept(t, r, "application/vnd.github+json")
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/repos/t4kamura/gh-wrun/actions/workflows?per_page=100&page=2>; rel="next", <http://%s/repos/t4kamura/gh-wrun/actions/workflows?per_page=100&page=2>; rel="last"`, r.Host, r.Host))
+			fmt.Fprint(w, `{"total_count":3,"workflows":[{"id":1,"name":"Test","path":".github/workflows/test.yml","state":"active"},{"id":2,"name":"Lint","path":".github/workflows/lint.yml","state":"disabled_manually"}]}`)
+		case "2":
+			fmt.Fprint(w, `{"total_count":3,"workflows":[{"id":3,"name":"Deploy","path":".github/workflows/deploy.yml","state":"active"}]}`)
+		default:
+			t.Errorf("Unexpected page: %s\n", r.URL.RawQuery)
+		}
+	})
+	c := newTestRestClient(t, mux)
+
+	testCases := []struct {
+		name string
+		all  bool
+		want []string
+	}{
+		{name: "active", all: false, want: []string{"Test", "Deploy"}},
+		{name: "all", all: true, want: []string{"Test", "Lint", "Deploy"}},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			workflows, err := c.ListWorkflows(context.Background(), test.all)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err)
+			}
+
+			got := []string{}
+			for _, w := range workflows {
+				got = append(got, w.Name)
+				if w.Repo != c.Repo {
+					t.Errorf("Expected is %v but got %v\n", c.Repo, w.Repo)
+				}
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Expected is %v but got %v\n", test.want, got)
+			}
+		})
+	}
+}
+
+func TestRestClientListEnvironments(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/t4kamura/gh-wrun/environments", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/repos/t4kamura/gh-wrun/environments?per_page=100&page=2>; rel="next"`, r.Host))
+			fmt.Fprint(w, `{"total_count":2,"environments":[{"name":"production"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"total_count":2,"environments":[{"name":"staging"}]}`)
+	})
+	c := newTestRestClient(t, mux)
+
+	got, err := c.ListEnvironments(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	want := []string{"production", "staging"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected is %v but got %v\n", want, got)
+	}
+}
+
+func TestRestClientGetWorkflowFile(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/t4kamura/gh-wrun/contents/.github/workflows/test.yml", func(w http.ResponseWriter, r *http.Request) {
+		wantAccept(t, r, "application/vnd.github.raw")
+		if r.URL.Query().Get("ref") != "main" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"No commit found for the ref feature","documentation_url":"https://docs.github.com/rest/repos/contents#get-repository-content"}`)
+			return
+		}
+		fmt.Fprint(w, "on: workflow_dispatch\n")
+	})
+	c := newTestRestClient(t, mux)
+	w := GhWorkflow{Id: "1", Path: ".github/workflows/test.yml"}
+
+	got, err := c.GetWorkflowFile(context.Background(), w, "main")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if string(got) != "on: workflow_dispatch\n" {
+		t.Errorf("Expected is %s but got %s\n", "on: workflow_dispatch\n", got)
+	}
+
+	_, err = c.GetWorkflowFile(context.Background(), w, "feature")
+	var ghErr *GhError
+	if !errors.As(err, &ghErr) {
+		t.Fatalf("Expected *GhError but got %v\n", err)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected is %v but got %v\n", ErrNotFound, err)
+	}
+	if ghErr.Message != "No commit found for the ref feature" {
+		t.Errorf("Expected is %s but got %s\n", "No commit found for the ref feature", ghErr.Message)
+	}
+}
+
+func TestRestClientDispatch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/t4kamura/gh-wrun/actions/workflows/1/dispatches", func(w http.ResponseWriter, r *http.Request) {
+		wantAccept(t, r, "application/vnd.github+json")
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected is %s but got %s\n", http.MethodPost, r.Method)
+		}
+
+		b, _ := io.ReadAll(r.Body)
+		var body struct {
+			Ref    string            `json:"ref"`
+			Inputs map[string]string `json:"inputs"`
+		}
+		if err := json.Unmarshal(b, &body); err != nil {
+			t.Errorf("Unexpected error: %s\n", err)
+		}
+		if _, ok := body.Inputs["unknown"]; ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"message":"Unexpected inputs provided: [\"unknown\"]"}`)
+			return
+		}
+		if body.Ref != "main" || body.Inputs["env"] != "staging" {
+			t.Errorf("Unexpected body: %s\n", b)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	c := newTestRestClient(t, mux)
+	w := GhWorkflow{Id: "1", Path: ".github/workflows/test.yml"}
+
+	if err := c.Dispatch(context.Background(), w, "main", []struct{ Key, Value string }{{"env", "staging"}}); err != nil {
+		t.Errorf("Unexpected error: %s\n", err)
+	}
+
+	err := c.Dispatch(context.Background(), w, "main", []struct{ Key, Value string }{{"unknown", "1"}})
+	if !errors.Is(err, ErrUnknownInput) {
+		t.Errorf("Expected is %v but got %v\n", ErrUnknownInput, err)
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	testCases := []struct {
+		name string
+		link string
+		want string
+	}{
+		{name: "empty", link: "", want: ""},
+		{
+			name: "next",
+			link: `<https://api.github.com/repositories/1/environments?page=2>; rel="next", <https://api.github.com/repositories/1/environments?page=3>; rel="last"`,
+			want: "https://api.github.com/repositories/1/environments?page=2",
+		},
+		{
+			name: "last page",
+			link: `<https://api.github.com/repositories/1/environments?page=1>; rel="prev", <https://api.github.com/repositories/1/environments?page=1>; rel="first"`,
+			want: "",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			if got := nextPageURL(test.link); got != test.want {
+				t.Errorf("Expected is %s but got %s\n", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package subproc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/t4kamura/gh-wrun/internal/progress"
+)
+
+type GhRun struct {
+	Id         json.Number `json:"databaseId"`
+	Number     int         `json:"number"`
+	Status     string      `json:"status"`
+	Conclusion string      `json:"conclusion"`
+	Url        string      `json:"url"`
+	HeadBranch string      `json:"headBranch"`
+	CreatedAt  time.Time   `json:"createdAt"`
+}
+
+const (
+	GhRunStatusCompleted = "completed"
+
+	GhRunConclusionSuccess   = "success"
+	GhRunConclusionFailure   = "failure"
+	GhRunConclusionCancelled = "cancelled"
+	GhRunConclusionTimedOut  = "timed_out"
+)
+
+const (
+	// runClockSkew allows for the difference between local and GitHub clocks
+	// when matching a run against the dispatch time.
+	runClockSkew = 10 * time.Second
+	// runPollInterval is the interval between polls for a dispatched run.
+	runPollInterval = 2 * time.Second
+	// runPollAttempts is the number of polls before giving up.
+	runPollAttempts = 30
+)
+
+// GetCurrentUser returns the login of the authenticated gh user on the host of repo.
+func GetCurrentUser(ctx context.Context, repo Repo) (string, error) {
+	out, err := repo.ghApi(ctx, "user", "--jq", ".login")
+	if err != nil {
+		return "", err
+	}
+
+	login := strings.TrimSpace(string(out))
+	if login == "" {
+		return "", errors.New("Error getting current user")
+	}
+	return login, nil
+}
+
+// FindDispatchedRun waits for the run created by a dispatch of the workflow
+// on ref at or after since, and returns it. If actor is not empty, only its runs are searched.
+// known are the runs listed before the dispatch, which cannot be the created one.
+func FindDispatchedRun(ctx context.Context, c Client, w GhWorkflow, ref, actor string, since time.Time, known []GhRun) (GhRun, error) {
+	defer progress.Start("Waiting for the run to start").Stop()
+
+	knownIds := map[json.Number]bool{}
+	for _, r := range known {
+		knownIds[r.Id] = true
+	}
+
+	for i := 0; i < runPollAttempts; i++ {
+		runs, err := c.ListDispatchRuns(ctx, w, ref, actor)
+		if err != nil {
+			return GhRun{}, err
+		}
+
+		if run, ok := selectDispatchedRun(runs, since, knownIds); ok {
+			return run, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return GhRun{}, ctx.Err()
+		case <-time.After(runPollInterval):
+		}
+	}
+
+	return GhRun{}, errors.New("Dispatched run not found")
+}
+
+// selectDispatchedRun returns the newest run created at or after since that is not known.
+// The run of a concurrent dispatch by the same actor cannot be told apart.
+func selectDispatchedRun(runs []GhRun, since time.Time, known map[json.Number]bool) (GhRun, bool) {
+	var (
+		found GhRun
+		ok    bool
+	)
+	for _, r := range runs {
+		if known[r.Id] || r.CreatedAt.Before(since.Add(-runClockSkew)) {
+			continue
+		}
+		if !ok || r.CreatedAt.After(found.CreatedAt) {
+			found = r
+			ok = true
+		}
+	}
+	return found, ok
+}
+
+// GetRun returns the run of repo with the given ID.
+func GetRun(ctx context.Context, repo Repo, id json.Number) (GhRun, error) {
+	out, err := repo.gh(ctx, "run", "view", string(id), "--json", "databaseId,number,status,conclusion,url,headBranch,createdAt")
+	if err != nil {
+		return GhRun{}, err
+	}
+
+	var run GhRun
+	if err := json.Unmarshal(out, &run); err != nil {
+		return GhRun{}, err
+	}
+	return run, nil
+}
+
+// WatchRun streams the progress of the run of repo to out until it completes.
+// It is only stopped by ctx, the run may take long.
+func WatchRun(ctx context.Context, repo Repo, id json.Number, out io.Writer) error {
+	cmd := interruptibleCommand(ctx, "gh", append([]string{"run", "watch", string(id)}, repo.repoArgs()...)...)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// OpenRun opens the run of repo in the browser.
+func OpenRun(ctx context.Context, repo Repo, id json.Number) error {
+	_, err := repo.gh(ctx, "run", "view", string(id), "--web")
+	return err
+}
+
+// String returns a short description of the run.
+func (r GhRun) String() string {
+	return "#" + strconv.Itoa(r.Number) + " " + r.Url
+}
@@ -0,0 +1,83 @@
+package subproc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSelectDispatchedRun(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		runs   []GhRun
+		known  map[json.Number]bool
+		wantId string
+		wantOk bool
+	}{
+		{
+			name:   "no runs",
+			runs:   []GhRun{},
+			wantOk: false,
+		},
+		{
+			name: "only older runs",
+			runs: []GhRun{
+				{Id: "1", CreatedAt: since.Add(-time.Hour)},
+			},
+			wantOk: false,
+		},
+		{
+			name: "run within clock skew",
+			runs: []GhRun{
+				{Id: "2", CreatedAt: since.Add(-5 * time.Second)},
+				{Id: "1", CreatedAt: since.Add(-time.Hour)},
+			},
+			wantId: "2",
+			wantOk: true,
+		},
+		{
+			name: "newest run after dispatch",
+			runs: []GhRun{
+				{Id: "4", CreatedAt: since.Add(30 * time.Second)},
+				{Id: "3", CreatedAt: since.Add(2 * time.Second)},
+				{Id: "1", CreatedAt: since.Add(-time.Hour)},
+			},
+			wantId: "4",
+			wantOk: true,
+		},
+		{
+			name: "run of a previous dispatch within clock skew",
+			runs: []GhRun{
+				{Id: "3", CreatedAt: since.Add(time.Second)},
+				{Id: "2", CreatedAt: since.Add(-5 * time.Second)},
+			},
+			known:  map[json.Number]bool{"2": true},
+			wantId: "3",
+			wantOk: true,
+		},
+		{
+			name: "only known runs",
+			runs: []GhRun{
+				{Id: "2", CreatedAt: since.Add(-5 * time.Second)},
+			},
+			known:  map[json.Number]bool{"2": true},
+			wantOk: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := selectDispatchedRun(test.runs, since, test.known)
+
+			if ok != test.wantOk {
+				t.Fatalf("Expected ok is %v but got %v\n", test.wantOk, ok)
+			}
+
+			if ok && string(got.Id) != test.wantId {
+				t.Errorf("Expected is %s but got %s\n", test.wantId, got.Id)
+			}
+		})
+	}
+}
@@ -1,14 +1,14 @@
 package table
 
 import (
-	"os"
+	"io"
 
 	"github.com/olekukonko/tablewriter"
 )
 
-// RenderTable renders a table to stdout
-func Render(d [][]string) {
-	table := tablewriter.NewWriter(os.Stdout)
+// Render renders a table to w
+func Render(w io.Writer, d [][]string) {
+	table := tablewriter.NewWriter(w)
 	table.AppendBulk(d)
 	table.SetRowLine(true)
 	table.SetAutoMergeCells(true)
@@ -2,8 +2,6 @@ package table
 
 import (
 	"bytes"
-	"io"
-	"os"
 	"testing"
 )
 
@@ -28,19 +26,8 @@ func TestRender(t *testing.T) {
 		"|         | server     | app      |\n" +
 		"+---------+------------+----------+\n"
 
-	orgStdout := os.Stdout
-	pr, pw, _ := os.Pipe()
-	os.Stdout = pw
-
-	Render(input)
-
-	pw.Close()
-	os.Stdout = orgStdout
-
 	buf := bytes.Buffer{}
-	if _, err := io.Copy(&buf, pr); err != nil {
-		t.Fatal(err)
-	}
+	Render(&buf, input)
 	output := buf.String()
 
 	if output != want {
@@ -1,12 +1,15 @@
 package version
 
 import (
+	"context"
+	"sort"
+
 	"github.com/hashicorp/go-version"
 	"github.com/t4kamura/gh-wrun/internal/subproc"
 )
 
-func CheckGhVersion(required string) (bool, error) {
-	v, err := subproc.GetGhVersion()
+func CheckGhVersion(ctx context.Context, required string) (bool, error) {
+	v, err := subproc.GetGhVersion(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -19,3 +22,33 @@ func CheckGhVersion(required string) (bool, error) {
 
 	return ver.GreaterThanOrEqual(requiredVer), err
 }
+
+// SortTags returns tags ordered from the newest version to the oldest.
+// Tags that are not versions follow in alphabetical order.
+func SortTags(tags []string) []string {
+	type tagVersion struct {
+		tag string
+		ver *version.Version
+	}
+
+	var versions []tagVersion
+	var others []string
+	for _, t := range tags {
+		if v, err := version.NewVersion(t); err == nil {
+			versions = append(versions, tagVersion{tag: t, ver: v})
+		} else {
+			others = append(others, t)
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].ver.GreaterThan(versions[j].ver)
+	})
+	sort.Strings(others)
+
+	sorted := make([]string, 0, len(tags))
+	for _, v := range versions {
+		sorted = append(sorted, v.tag)
+	}
+	return append(sorted, others...)
+}
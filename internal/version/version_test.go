@@ -0,0 +1,44 @@
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{
+			name: "empty",
+			tags: []string{},
+			want: []string{},
+		},
+		{
+			name: "versions",
+			tags: []string{"v1.2.0", "v1.10.0", "v1.4.2", "v0.9.1"},
+			want: []string{"v1.10.0", "v1.4.2", "v1.2.0", "v0.9.1"},
+		},
+		{
+			name: "prerelease",
+			tags: []string{"v1.4.2-rc.1", "v1.4.2", "v1.4.1"},
+			want: []string{"v1.4.2", "v1.4.2-rc.1", "v1.4.1"},
+		},
+		{
+			name: "versions and others",
+			tags: []string{"nightly", "v1.0.0", "latest", "v2.0.0"},
+			want: []string{"v2.0.0", "v1.0.0", "latest", "nightly"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SortTags(tt.tags)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}